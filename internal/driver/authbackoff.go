@@ -0,0 +1,149 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/IOTechSystems/onvif"
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+)
+
+// errAuthBackoff is wrapped by the errors returned instead of sending an authenticated request to a camera which
+// rejected too many of them
+var errAuthBackoff = stdErrors.New("authenticated requests are suspended")
+
+// authFailed records an authenticated request rejected by the camera, and suspends the authenticated requests to the
+// camera until now + backoff once threshold consecutive requests have been rejected, or as soon as the camera reports
+// that the user is locked out. Returns the end of the backoff, and true if the backoff was just started.
+func (t *deviceHealthTracker) authFailed(deviceName string, lockedOut bool, threshold int, backoff time.Duration,
+	now time.Time) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.devices == nil {
+		t.devices = make(map[string]*DeviceHealth)
+	}
+	health, found := t.devices[deviceName]
+	if !found {
+		health = &DeviceHealth{DeviceName: deviceName, Level: statusLevel("")}
+		t.devices[deviceName] = health
+	}
+
+	health.ConsecutiveAuthFailures++
+	if threshold <= 0 || (health.ConsecutiveAuthFailures < threshold && !lockedOut) {
+		return time.Time{}, false
+	}
+	started := health.AuthBackoffUntil == nil || !now.Before(*health.AuthBackoffUntil)
+	until := now.Add(backoff)
+	health.AuthBackoffUntil = &until
+	return until, started
+}
+
+// authSucceeded clears the authentication failures of the camera, and returns true if there were any
+func (t *deviceHealthTracker) authSucceeded(deviceName string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health, found := t.devices[deviceName]
+	if !found || (health.ConsecutiveAuthFailures == 0 && health.AuthBackoffUntil == nil) {
+		return false
+	}
+	health.ConsecutiveAuthFailures = 0
+	health.AuthBackoffUntil = nil
+	return true
+}
+
+// authBackoffUntil returns the end of the backoff of the camera, and true if its authenticated requests are suspended
+func (t *deviceHealthTracker) authBackoffUntil(deviceName string, now time.Time) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health, found := t.devices[deviceName]
+	if !found || health.AuthBackoffUntil == nil || !now.Before(*health.AuthBackoffUntil) {
+		return time.Time{}, false
+	}
+	return *health.AuthBackoffUntil, true
+}
+
+// authBackoffConfig returns the number of consecutive authentication failures after which the authenticated requests
+// to a camera are suspended, which is zero if disabled, and for how long
+func (d *Driver) authBackoffConfig() (int, time.Duration) {
+	d.configMu.RLock()
+	defer d.configMu.RUnlock()
+
+	return d.config.AppCustom.AuthFailureThreshold, time.Duration(d.config.AppCustom.AuthBackoffSeconds) * time.Second
+}
+
+// checkAuthBackoff returns an error if the authenticated requests to the camera are currently suspended
+func (d *Driver) checkAuthBackoff(deviceName string) errors.EdgeX {
+	until, backingOff := d.health.authBackoffUntil(deviceName, time.Now())
+	if !backingOff {
+		return nil
+	}
+	return errors.NewCommonEdgeX(errors.KindServiceLocked,
+		fmt.Sprintf("authenticated requests to the camera %s are suspended until %s after repeated authentication failures",
+			deviceName, until.Format(time.RFC3339)), errAuthBackoff)
+}
+
+// recordAuthResult records the outcome of an authenticated request to the camera. A rejection counts towards the
+// backoff of the camera, and a success ends it. Other errors, such as timeouts, are not counted.
+func (d *Driver) recordAuthResult(deviceName string, edgexErr errors.EdgeX) {
+	if edgexErr == nil {
+		if d.health.authSucceeded(deviceName) {
+			d.lc.Debugf("Device %s authenticated successfully, its authentication failures are cleared", deviceName)
+		}
+		return
+	}
+	lockedOut := isLockoutError(edgexErr)
+	if !lockedOut && !isAuthError(edgexErr) {
+		return
+	}
+
+	threshold, backoff := d.authBackoffConfig()
+	if until, started := d.health.authFailed(deviceName, lockedOut, threshold, backoff, time.Now()); started {
+		d.lc.Warnf("Suspending the authenticated requests to device %s until %s after repeated authentication failures. "+
+			"They resume after the backoff, or once the secret of the device is updated.", deviceName, until.Format(time.RFC3339))
+	}
+}
+
+// resetAuthBackoff clears the authentication failures of the cameras, such as after their secret is updated
+func (d *Driver) resetAuthBackoff(deviceNames ...string) {
+	for _, deviceName := range deviceNames {
+		if d.health.authSucceeded(deviceName) {
+			d.lc.Infof("Resuming the authenticated requests to device %s", deviceName)
+		}
+	}
+}
+
+// requiresAuth returns true if the command request sends an onvif function which requires authentication
+func requiresAuth(req sdkModel.CommandRequest, functionType string) bool {
+	serviceName, edgexErr := attributeByKey(req.Attributes, Service)
+	if edgexErr != nil || serviceName == EdgeXWebService {
+		return false
+	}
+	functionName, _ := attributeByKey(req.Attributes, functionType)
+	return functionName != onvif.GetSystemDateAndTime
+}
+
+// callOnvifFunctionWithBackoff sends the command request to the camera, unless it requires authentication while the
+// authenticated requests to the camera are suspended, and records the outcome of the authenticated requests
+func (d *Driver) callOnvifFunctionWithBackoff(deviceName string, onvifClient *OnvifClient, req sdkModel.CommandRequest,
+	functionType string, data []byte) (*sdkModel.CommandValue, errors.EdgeX) {
+	if !requiresAuth(req, functionType) {
+		return onvifClient.CallOnvifFunction(req, functionType, data)
+	}
+	if edgexErr := d.checkAuthBackoff(deviceName); edgexErr != nil {
+		return nil, edgexErr
+	}
+	cv, edgexErr := onvifClient.CallOnvifFunction(req, functionType, data)
+	d.recordAuthResult(deviceName, edgexErr)
+	return cv, edgexErr
+}
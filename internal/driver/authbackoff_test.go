@@ -0,0 +1,114 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IOTechSystems/onvif"
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-bootstrap/v4/bootstrap/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceHealthTracker_authBackoff(t *testing.T) {
+	tracker := deviceHealthTracker{}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	backoff := time.Minute
+
+	_, started := tracker.authFailed("camera", false, 2, backoff, now)
+	assert.False(t, started)
+	_, backingOff := tracker.authBackoffUntil("camera", now)
+	assert.False(t, backingOff)
+
+	until, started := tracker.authFailed("camera", false, 2, backoff, now)
+	assert.True(t, started)
+	assert.Equal(t, now.Add(backoff), until)
+	actual, backingOff := tracker.authBackoffUntil("camera", now.Add(backoff/2))
+	assert.True(t, backingOff)
+	assert.Equal(t, until, actual)
+	health, _ := tracker.get("camera")
+	assert.Equal(t, 2, health.ConsecutiveAuthFailures)
+
+	// the failure of the first request after the backoff starts a new backoff straight away
+	_, backingOff = tracker.authBackoffUntil("camera", now.Add(backoff))
+	assert.False(t, backingOff)
+	_, started = tracker.authFailed("camera", false, 2, backoff, now.Add(backoff))
+	assert.True(t, started)
+
+	assert.True(t, tracker.authSucceeded("camera"))
+	assert.False(t, tracker.authSucceeded("camera"))
+	_, backingOff = tracker.authBackoffUntil("camera", now.Add(backoff))
+	assert.False(t, backingOff)
+
+	// a lockout starts the backoff before the threshold is reached
+	_, started = tracker.authFailed("camera", true, 2, backoff, now)
+	assert.True(t, started)
+
+	// the backoff is disabled if the threshold is zero
+	_, started = tracker.authFailed("other", true, 0, backoff, now)
+	assert.False(t, started)
+	health, _ = tracker.get("other")
+	assert.Equal(t, 1, health.ConsecutiveAuthFailures)
+	assert.Nil(t, health.AuthBackoffUntil)
+}
+
+func TestDriver_authBackoff(t *testing.T) {
+	const secretName = "camera"
+
+	driver, mockService := createDriverWithMockService()
+	driver.config.AppCustom.DefaultSecretName = secretName
+	driver.config.AppCustom.AuthFailureThreshold = 2
+	driver.config.AppCustom.AuthBackoffSeconds = 900
+	mockSecretProvider := &mocks.SecretProvider{}
+	mockSecretProvider.On("GetSecret", secretName, UsernameKey, PasswordKey, AuthModeKey).
+		Return(map[string]string{UsernameKey: "admin", PasswordKey: "WrongPassword1", AuthModeKey: AuthModeUsernameToken}, nil)
+	mockService.On("SecretProvider").Return(mockSecretProvider)
+
+	camera := newFakeCamera(t, "Password1")
+	device := camera.device("camera")
+	client, edgexErr := driver.newTemporaryOnvifClient(device)
+	require.NoError(t, edgexErr)
+	driver.onvifClients[device.Name] = client
+
+	for i := 1; i <= 2; i++ {
+		observation := driver.testConnectionMethods(device)
+		assert.Equal(t, UpWithoutAuth, observation.status)
+		assert.Equal(t, HealthErrorAuth, classifyHealthError(observation.err))
+		assert.Equal(t, i, camera.rejectedRequests())
+	}
+
+	// the status check and the commands no longer send authenticated requests
+	observation := driver.testConnectionMethods(device)
+	assert.Equal(t, UpWithoutAuth, observation.status)
+	assert.Equal(t, HealthErrorAuthBackoff, classifyHealthError(observation.err))
+
+	readDeviceInformation := sdkModel.CommandRequest{DeviceResourceName: onvif.GetDeviceInformation,
+		Attributes: map[string]any{Service: onvif.DeviceWebService, GetFunction: onvif.GetDeviceInformation}}
+	_, edgexErr = driver.callOnvifFunctionWithBackoff(device.Name, client, readDeviceInformation, GetFunction, nil)
+	require.Error(t, edgexErr)
+	assert.Equal(t, errors.KindServiceLocked, errors.Kind(edgexErr))
+	assert.Equal(t, 2, camera.rejectedRequests())
+
+	readDateTime := sdkModel.CommandRequest{DeviceResourceName: onvif.GetSystemDateAndTime,
+		Attributes: map[string]any{Service: onvif.DeviceWebService, GetFunction: onvif.GetSystemDateAndTime}}
+	_, edgexErr = driver.callOnvifFunctionWithBackoff(device.Name, client, readDateTime, GetFunction, nil)
+	require.NoError(t, edgexErr)
+
+	health := driver.deviceHealth(device)
+	assert.Equal(t, 2, health.ConsecutiveAuthFailures)
+	require.NotNil(t, health.AuthBackoffUntil)
+
+	// a secret update resumes the authenticated requests
+	driver.resetAuthBackoff(device.Name)
+	observation = driver.testConnectionMethods(device)
+	assert.Equal(t, HealthErrorAuth, classifyHealthError(observation.err))
+	assert.Equal(t, 3, camera.rejectedRequests())
+}
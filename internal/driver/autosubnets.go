@@ -0,0 +1,113 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"net"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+)
+
+const (
+	// AutoSubnets is the DiscoverySubnets value which is replaced with the subnets of the local network interfaces
+	AutoSubnets = "auto"
+	// defaultAutoSubnetMaxSize is the prefix length used when DiscoveryAutoSubnetMaxSize is not valid
+	defaultAutoSubnetMaxSize = 24
+)
+
+// expandAutoSubnets replaces any AutoSubnets entry of subnets with the subnets of the local network interfaces.
+// If ifaceNames is not empty or AllInterfaces, only the subnets of those interfaces are used.
+func (d *Driver) expandAutoSubnets(subnets []string, ifaceNames []string, maxSize int) []string {
+	expanded := make([]string, 0, len(subnets))
+	for _, subnet := range subnets {
+		if !strings.EqualFold(strings.TrimSpace(subnet), AutoSubnets) {
+			expanded = append(expanded, subnet)
+			continue
+		}
+
+		lookupNames := ifaceNames
+		if len(lookupNames) == 0 {
+			lookupNames = []string{AllInterfaces}
+		}
+		ifaces, edgexErr := d.lookupInterfaces(lookupNames)
+		if edgexErr != nil {
+			d.lc.Errorf("Unable to auto detect the local subnets: %s", edgexErr.Error())
+			continue
+		}
+		autoSubnets, err := localInterfaceSubnets(ifaces, maxSize)
+		if err != nil {
+			d.lc.Errorf("Unable to auto detect the local subnets: %s", err.Error())
+			continue
+		}
+		if len(autoSubnets) == 0 {
+			d.lc.Warnf("Unable to auto detect any local IPv4 subnets. interfaces=%v", ifaceNames)
+			continue
+		}
+		d.lc.Infof("Auto detected the following local subnets for netscan discovery: %v", autoSubnets)
+		expanded = append(expanded, autoSubnets...)
+	}
+	return expanded
+}
+
+// localInterfaceSubnets returns the IPv4 subnets of the up and non-loopback network interfaces among ifaces
+func localInterfaceSubnets(ifaces []net.Interface, maxSize int) ([]string, error) {
+	var addrs []net.Addr
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		ifaceAddrs, err := iface.Addrs()
+		if err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindServerError, "failed to list the addresses of the network interface "+iface.Name, err)
+		}
+		addrs = append(addrs, ifaceAddrs...)
+	}
+	return subnetsFromAddrs(addrs, maxSize), nil
+}
+
+// subnetsFromAddrs converts the IPv4 interface addresses into unique CIDR formatted subnets. Subnets larger
+// than the maxSize prefix length are reduced to the subnet of that size which contains the interface address.
+func subnetsFromAddrs(addrs []net.Addr, maxSize int) []string {
+	if maxSize <= 0 || maxSize > 32 {
+		maxSize = defaultAutoSubnetMaxSize
+	}
+
+	seen := make(map[string]struct{})
+	var subnets []string
+	for _, addr := range addrs {
+		ipnet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		ip := ipnet.IP.To4()
+		if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+			continue
+		}
+
+		ones, bits := ipnet.Mask.Size()
+		if bits != 8*net.IPv4len && bits != 8*net.IPv6len {
+			continue
+		}
+		if bits == 8*net.IPv6len {
+			// an IPv4 address with a 16 byte mask
+			ones -= 8 * (net.IPv6len - net.IPv4len)
+		}
+		if ones < maxSize {
+			ones = maxSize
+		}
+
+		mask := net.CIDRMask(ones, 8*net.IPv4len)
+		subnet := (&net.IPNet{IP: ip.Mask(mask), Mask: mask}).String()
+		if _, found := seen[subnet]; found {
+			continue
+		}
+		seen[subnet] = struct{}{}
+		subnets = append(subnets, subnet)
+	}
+	return subnets
+}
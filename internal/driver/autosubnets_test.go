@@ -0,0 +1,83 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubnetsFromAddrs(t *testing.T) {
+	ipNet := func(ip string, prefix int, bits int) *net.IPNet {
+		return &net.IPNet{IP: net.ParseIP(ip), Mask: net.CIDRMask(prefix, bits)}
+	}
+
+	tests := []struct {
+		name     string
+		addrs    []net.Addr
+		maxSize  int
+		expected []string
+	}{
+		{
+			name:     "subnet within the max size",
+			addrs:    []net.Addr{ipNet("192.168.1.20", 24, 32)},
+			maxSize:  24,
+			expected: []string{"192.168.1.0/24"},
+		},
+		{
+			name:     "smaller subnet is kept",
+			addrs:    []net.Addr{ipNet("192.168.1.20", 28, 32)},
+			maxSize:  24,
+			expected: []string{"192.168.1.16/28"},
+		},
+		{
+			name:     "oversized subnet is capped",
+			addrs:    []net.Addr{ipNet("10.20.30.40", 8, 32)},
+			maxSize:  22,
+			expected: []string{"10.20.28.0/22"},
+		},
+		{
+			name:     "ipv4 address with 16 byte mask",
+			addrs:    []net.Addr{ipNet("10.20.30.40", 112, 128)},
+			maxSize:  24,
+			expected: []string{"10.20.30.0/24"},
+		},
+		{
+			name:     "invalid max size uses the default",
+			addrs:    []net.Addr{ipNet("172.16.5.9", 16, 32)},
+			maxSize:  0,
+			expected: []string{"172.16.5.0/24"},
+		},
+		{
+			name: "duplicates, ipv6, loopback and link local are skipped",
+			addrs: []net.Addr{
+				ipNet("192.168.1.20", 24, 32),
+				ipNet("192.168.1.21", 24, 32),
+				ipNet("fe80::1", 64, 128),
+				ipNet("2001:db8::1", 64, 128),
+				ipNet("127.0.0.1", 8, 32),
+				ipNet("169.254.10.10", 16, 32),
+				&net.IPAddr{IP: net.ParseIP("10.0.0.1")},
+			},
+			maxSize:  24,
+			expected: []string{"192.168.1.0/24"},
+		},
+		{
+			name:    "no addresses",
+			maxSize: 24,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, subnetsFromAddrs(test.addrs, test.maxSize))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	onvifdevice "github.com/IOTechSystems/onvif/device"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+)
+
+// Capability flags stored in the onvif protocol properties of discovered cameras. The values are "true" or "false",
+// so that they can be used as provision watcher identifiers. A flag is only "false" when both GetCapabilities and
+// GetServices succeeded, and is left out when the service could not be ruled out, so that the camera is matched by the
+// provision watchers which do not use the flag.
+const (
+	SupportsPTZ       = "SupportsPTZ"
+	SupportsImaging   = "SupportsImaging"
+	SupportsAnalytics = "SupportsAnalytics"
+	SupportsMedia2    = "SupportsMedia2"
+	SupportsEvents    = "SupportsEvents"
+	SupportsDeviceIO  = "SupportsDeviceIO"
+)
+
+// capabilityServices maps each capability flag to the endpoint key of the GetCapabilities response, and the
+// namespace of the service in the GetServices response. Media2 has no GetCapabilities category, and the onvif
+// library always aliases the media endpoint as media2, so it is only detected via GetServices.
+var capabilityServices = []struct {
+	flag        string
+	endpointKey string
+	namespace   string
+}{
+	{flag: SupportsPTZ, endpointKey: "ptz", namespace: "http://www.onvif.org/ver20/ptz/wsdl"},
+	{flag: SupportsImaging, endpointKey: "imaging", namespace: "http://www.onvif.org/ver20/imaging/wsdl"},
+	{flag: SupportsAnalytics, endpointKey: "analytics", namespace: "http://www.onvif.org/ver20/analytics/wsdl"},
+	{flag: SupportsMedia2, namespace: "http://www.onvif.org/ver20/media/wsdl"},
+	{flag: SupportsEvents, endpointKey: "events", namespace: "http://www.onvif.org/ver10/events/wsdl"},
+	{flag: SupportsDeviceIO, endpointKey: "deviceio", namespace: "http://www.onvif.org/ver10/deviceIO/wsdl"},
+}
+
+// deviceEndpointKey is the key of the device service endpoint, which the onvif library adds before GetCapabilities
+const deviceEndpointKey = "device"
+
+// getServicesEnvelope is the SOAP envelope of a GetServices response. The onvif library only unmarshals a
+// single Service, so the response is parsed here instead.
+type getServicesEnvelope struct {
+	Services []struct {
+		Namespace string `xml:"Namespace"`
+	} `xml:"Body>GetServicesResponse>Service"`
+}
+
+// getCapabilityFlags determines which of the optional onvif services the camera supports. The endpoints returned by
+// GetCapabilities when the client was created are combined with the namespaces returned by GetServices. If either
+// request failed, only the supported services are flagged, and the other flags are left out.
+func (onvifClient *OnvifClient) getCapabilityFlags(deviceName string) map[string]string {
+	namespaces, err := onvifClient.getServiceNamespaces()
+	if err != nil {
+		onvifClient.lc.Debugf("Unable to get the services of the camera %s, only its supported capabilities are flagged: %s", deviceName, err.Error())
+	}
+
+	endpoints := make(map[string]struct{})
+	for key := range onvifClient.onvifDevice.GetServices() {
+		endpoints[strings.ToLower(key)] = struct{}{}
+	}
+	// the device endpoint is always known, so the GetCapabilities response only provided endpoints if there are others
+	_, hasDeviceEndpoint := endpoints[deviceEndpointKey]
+	capabilitiesKnown := len(endpoints) > 1 || (len(endpoints) == 1 && !hasDeviceEndpoint)
+
+	flags := make(map[string]string, len(capabilityServices))
+	for _, service := range capabilityServices {
+		_, hasEndpoint := endpoints[service.endpointKey]
+		_, hasNamespace := namespaces[strings.ToLower(service.namespace)]
+		supported := (service.endpointKey != "" && hasEndpoint) || hasNamespace
+		if supported || (err == nil && capabilitiesKnown) {
+			flags[service.flag] = strconv.FormatBool(supported)
+		}
+	}
+	return flags
+}
+
+// getServiceNamespaces sends a GetServices request to the camera and returns the lower case namespaces of its services
+func (onvifClient *OnvifClient) getServiceNamespaces() (map[string]struct{}, errors.EdgeX) {
+	resp, err := onvifClient.onvifDevice.CallMethod(onvifdevice.GetServices{})
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindServerError, "failed to send the GetServices request", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindServerError, "failed to read the GetServices response", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("unexpected GetServices response status %d", resp.StatusCode), nil)
+	}
+
+	envelope := getServicesEnvelope{}
+	if err = xml.Unmarshal(data, &envelope); err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindServerError, "failed to parse the GetServices response", err)
+	}
+
+	namespaces := make(map[string]struct{}, len(envelope.Services))
+	for _, service := range envelope.Services {
+		namespaces[strings.ToLower(strings.TrimSpace(service.Namespace))] = struct{}{}
+	}
+	return namespaces, nil
+}
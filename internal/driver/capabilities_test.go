@@ -0,0 +1,99 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const getServicesResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<SOAP-ENV:Envelope xmlns:SOAP-ENV="http://www.w3.org/2003/05/soap-envelope" xmlns:tds="http://www.onvif.org/ver10/device/wsdl">
+<SOAP-ENV:Body><tds:GetServicesResponse>
+<tds:Service><tds:Namespace>http://www.onvif.org/ver10/device/wsdl</tds:Namespace><tds:XAddr>http://192.168.1.10/onvif/device_service</tds:XAddr></tds:Service>
+<tds:Service><tds:Namespace>http://www.onvif.org/ver10/media/wsdl</tds:Namespace><tds:XAddr>http://192.168.1.10/onvif/media_service</tds:XAddr></tds:Service>
+<tds:Service><tds:Namespace>http://www.onvif.org/ver20/media/wsdl</tds:Namespace><tds:XAddr>http://192.168.1.10/onvif/media2_service</tds:XAddr></tds:Service>
+<tds:Service><tds:Namespace>http://www.onvif.org/ver10/deviceIO/wsdl</tds:Namespace><tds:XAddr>http://192.168.1.10/onvif/deviceio_service</tds:XAddr></tds:Service>
+</tds:GetServicesResponse></SOAP-ENV:Body></SOAP-ENV:Envelope>`
+
+func TestOnvifClient_getCapabilityFlags(t *testing.T) {
+	endpoints := map[string]string{
+		"device":  "http://192.168.1.10/onvif/device_service",
+		"media":   "http://192.168.1.10/onvif/media_service",
+		"media2":  "http://192.168.1.10/onvif/media_service",
+		"events":  "http://192.168.1.10/onvif/event_service",
+		"imaging": "http://192.168.1.10/onvif/imaging_service",
+	}
+
+	tests := []struct {
+		name        string
+		endpoints   map[string]string
+		response    *http.Response
+		responseErr error
+		expected    map[string]string
+	}{
+		{
+			name:     "capabilities and services",
+			response: &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(getServicesResponse))},
+			expected: map[string]string{
+				SupportsPTZ:       "false",
+				SupportsImaging:   "true",
+				SupportsAnalytics: "false",
+				SupportsMedia2:    "true",
+				SupportsEvents:    "true",
+				SupportsDeviceIO:  "true",
+			},
+		},
+		{
+			name:        "services request failed",
+			responseErr: errors.New("connection refused"),
+			expected: map[string]string{
+				SupportsImaging: "true",
+				SupportsEvents:  "true",
+			},
+		},
+		{
+			name:     "services not authorized",
+			response: &http.Response{StatusCode: http.StatusUnauthorized, Body: io.NopCloser(strings.NewReader(soapFaultResponse))},
+			expected: map[string]string{
+				SupportsImaging: "true",
+				SupportsEvents:  "true",
+			},
+		},
+		{
+			name:      "capabilities unknown",
+			endpoints: map[string]string{"device": "http://192.168.1.10/onvif/device_service"},
+			response:  &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(getServicesResponse))},
+			expected: map[string]string{
+				SupportsMedia2:   "true",
+				SupportsDeviceIO: "true",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			driver, _ := createDriverWithMockService()
+			onvifClient, mockDevice := createOnvifClientWithMockDevice(driver, testDeviceName)
+			if test.endpoints == nil {
+				test.endpoints = endpoints
+			}
+			mockDevice.On("GetServices").Return(test.endpoints).Once()
+			mockDevice.On("CallMethod", mock.Anything).Return(test.response, test.responseErr).Once()
+
+			assert.Equal(t, test.expected, onvifClient.getCapabilityFlags(testDeviceName))
+			mockDevice.AssertExpectations(t)
+		})
+	}
+}
@@ -8,9 +8,12 @@
 package driver
 
 import (
+	"math/rand/v2"
 	"net"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/IOTechSystems/onvif"
@@ -20,26 +23,88 @@ import (
 	"github.com/spf13/cast"
 )
 
-// checkStatuses loops through all registered devices and tries to determine the most accurate connection state
-func (d *Driver) checkStatuses() {
-	d.lc.Debug("checkStatuses has been called")
-	start := time.Now()
-	defer func() {
-		d.lc.Debugf("checkStatuses completed in: %v", time.Since(start))
-	}()
+// statusCheckRoundTimeout returns the time within which a round checking the status of all the devices must complete,
+// which is the CheckStatusInterval
+func (d *Driver) statusCheckRoundTimeout() time.Duration {
+	_, intervals := d.statusCheckConfig()
+	return min(max(intervals.normal, time.Second), intervals.max)
+}
 
-	wg := sync.WaitGroup{}
-	for _, device := range d.sdkService.Devices() {
-		device := device // save the device value within the closure
+// checkStatusOfDevices checks the status of the specified devices using a bounded pool of workers. The start of each
+// check is spread out by a random delay of up to StatusCheckJitterMillis, limited to half of the round timeout. Devices
+// whose check has not started when the round timeout elapses are skipped, so that a slow round never overlaps the next.
+func (d *Driver) checkStatusOfDevices(devices []models.Device, timeout time.Duration) {
+	d.configMu.RLock()
+	workers := d.config.AppCustom.StatusCheckWorkers
+	jitter := time.Duration(d.config.AppCustom.StatusCheckJitterMillis) * time.Millisecond
+	d.configMu.RUnlock()
+
+	if workers <= 0 || workers > len(devices) {
+		workers = len(devices)
+	}
+	jitter = min(jitter, timeout/2)
+
+	round := StatusCheckRound{StartedAt: time.Now(), Devices: len(devices), Workers: workers}
+	deadline := round.StartedAt.Add(timeout)
 
+	// assign each device a random start time within the jitter, and queue them in the order they are due to start
+	type scheduledCheck struct {
+		device models.Device
+		start  time.Time
+	}
+	checks := make([]scheduledCheck, 0, len(devices))
+	for _, device := range devices {
+		check := scheduledCheck{device: device, start: round.StartedAt}
+		if jitter > 0 {
+			check.start = check.start.Add(rand.N(jitter))
+		}
+		checks = append(checks, check)
+	}
+	slices.SortFunc(checks, func(a, b scheduledCheck) int { return a.start.Compare(b.start) })
+
+	queue := make(chan scheduledCheck)
+	var checked, skipped atomic.Int64
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 
-			d.checkStatusOfDevice(device)
+			for check := range queue {
+				if wait := time.Until(check.start); wait > 0 {
+					select {
+					case <-d.taskCh: // the driver is stopping
+						skipped.Add(1)
+						continue
+					case <-time.After(wait):
+					}
+				}
+				if time.Now().After(deadline) {
+					skipped.Add(1)
+					continue
+				}
+				d.checkStatusOfDevice(check.device)
+				checked.Add(1)
+			}
 		}()
 	}
+	for _, check := range checks {
+		queue <- check
+	}
+	close(queue)
 	wg.Wait()
+
+	round.Duration = time.Since(round.StartedAt)
+	round.Checked = int(checked.Load())
+	round.Skipped = int(skipped.Load())
+	d.statusCheckMetrics.record(round)
+	d.flushLastSeenUpdates()
+
+	d.lc.Debugf("Checked the status of %d devices in %v using %d workers", round.Checked, round.Duration, round.Workers)
+	if round.Skipped > 0 {
+		d.lc.Warnf("Skipped the status check of %d devices which could not be checked within %v. Consider increasing StatusCheckWorkers.",
+			round.Skipped, timeout)
+	}
 }
 
 // checkStatusOfDevice checks the status of an individual device
@@ -61,57 +126,117 @@ func (d *Driver) checkStatusOfDevice(device models.Device) {
 		}
 	}
 
-	status := d.testConnectionMethods(device)
-	if statusChanged, updateDeviceStatusErr := d.updateDeviceStatus(device.Name, status); updateDeviceStatusErr != nil {
+	observation := d.testConnectionMethods(device)
+
+	var relocation map[string]any
+	if observation.status == Unreachable {
+		// the device may have been given a new IP address, so try and find it before the next discovery does.
+		// updating the address will cause the onvif client to be re-created, and the status to be checked again.
+		if relocation = d.relocateDevice(device); relocation != nil {
+			observation.status = Reachable
+		}
+	}
+
+	// the status only changes once it has been observed by enough consecutive checks, so that a single timeout
+	// does not cause it to flap
+	currentStatus := cast.ToString(device.Protocols[OnvifProtocol][DeviceStatus])
+	status := d.health.observe(device.Name, currentStatus, observation, d.statusThresholds(), time.Now())
+
+	if statusChanged, updateDeviceStatusErr := d.updateDeviceStatusAndProperties(device.Name, status, relocation); updateDeviceStatusErr != nil {
 		d.lc.Warnf("Could not update device status for device %s: %s", device.Name, updateDeviceStatusErr.Error())
 
-	} else if statusChanged && status == UpWithAuth {
-		d.lc.Infof("Device %s is now %s, refreshing the device information.", device.Name, UpWithAuth)
-		go func() { // refresh the device information in the background
-			if refreshErr := d.refreshDevice(device); refreshErr != nil {
-				d.lc.Errorf("An error occurred while refreshing the device %s: %s",
-					device.Name, refreshErr.Error())
-			}
-		}()
+	} else if statusChanged {
+		d.publishStatusChange(device, currentStatus, status, observation, relocation != nil)
+
+		if status == UpWithAuth {
+			d.lc.Infof("Device %s is now %s, refreshing the device information.", device.Name, UpWithAuth)
+			go func() { // refresh the device information in the background
+				if refreshErr := d.refreshDevice(device); refreshErr != nil {
+					d.lc.Errorf("An error occurred while refreshing the device %s: %s",
+						device.Name, refreshErr.Error())
+				}
+			}()
+		}
+	}
+
+	d.statusSchedule.record(device.Name, status, time.Now())
+
+	// keep track of the devices which are no longer seen, once their status has been updated
+	if status == Unreachable {
+		d.deviceMissedStatusCheck(device)
+	} else {
+		d.deviceSeen(device)
 	}
 
 	d.lc.Debugf("device %s status is %s", device.Name, status)
 }
 
 // testConnectionMethods will try to determine the state using different device calls
-// and return the most accurate status
+// and return the most accurate status, along with the latency and clock skew of the camera
 // Higher degrees of connection are tested first, because if they
-// succeed, the lower levels of connection will too
-func (d *Driver) testConnectionMethods(device models.Device) (status string) {
+// succeed, the lower levels of connection will too. The unauthenticated time of the camera is
+// requested beforehand, as it is needed to authenticate with a camera whose clock has drifted
+func (d *Driver) testConnectionMethods(device models.Device) healthObservation {
 	devClient, err := d.getOrCreateOnvifClient(device)
 	if err != nil {
 		d.lc.Warnf("Error getting onvif client for device %s", device.Name)
 		// if we do not have a valid onvif client, lets just tcp probe it
 		if d.tcpProbe(device) {
-			return Reachable
+			return healthObservation{status: Reachable, err: err}
 		}
-		return Unreachable
+		return healthObservation{status: Unreachable, err: err}
 	}
 
-	// sends GetDeviceInformation command to device (requires authentication)
-	_, edgexErr := devClient.callOnvifFunction(onvif.DeviceWebService, onvif.GetDeviceInformation, []byte{})
-	if edgexErr == nil {
-		return UpWithAuth // we are authenticated
+	// sends GetSystemDateAndTime command to device (does not require authentication). This is sent first, so that
+	// the WS-UsernameToken of the authenticated command can be adjusted when the clock of the camera has drifted.
+	var observation healthObservation
+	dateTimeSent := time.Now()
+	response, dateTimeErr := devClient.callOnvifFunction(onvif.DeviceWebService, onvif.GetSystemDateAndTime, []byte{})
+	dateTimeReceived := time.Now()
+	if dateTimeErr == nil {
+		if observation.clockSkew = clockSkewFromResponse(response, dateTimeSent, dateTimeReceived); observation.clockSkew != nil {
+			d.compensateClockSkew(devClient, *observation.clockSkew)
+		}
+	} else {
+		d.lc.Debugf("%s command failed for device %s without using authentication: %s", onvif.GetSystemDateAndTime, device.Name, dateTimeErr.Message())
 	}
-	d.lc.Debugf("%s command failed for device %s when using authentication: %s", onvif.GetDeviceInformation, device.Name, edgexErr.Message())
 
-	// sends GetSystemDateAndTime command to device (does not require authentication)
-	_, edgexErr = devClient.callOnvifFunction(onvif.DeviceWebService, onvif.GetSystemDateAndTime, []byte{})
+	// sends GetDeviceInformation command to device (requires authentication), unless the camera rejected too many
+	// authenticated requests, in which case only the unauthenticated command is used until the backoff ends
+	edgexErr := d.checkAuthBackoff(device.Name)
 	if edgexErr == nil {
-		return UpWithoutAuth // non-authenticated onvif command is working
+		sent := time.Now()
+		_, edgexErr = devClient.callOnvifFunction(onvif.DeviceWebService, onvif.GetDeviceInformation, []byte{})
+		d.recordAuthResult(device.Name, edgexErr)
+		if edgexErr == nil {
+			observation.status = UpWithAuth // we are authenticated
+			observation.latency = time.Since(sent)
+			if observation.clockSkew != nil {
+				d.syncCameraClock(devClient, *observation.clockSkew)
+			}
+			return observation
+		}
+		d.lc.Debugf("%s command failed for device %s when using authentication: %s", onvif.GetDeviceInformation, device.Name, edgexErr.Message())
+	}
+	observation.err = edgexErr
+
+	if dateTimeErr == nil {
+		observation.status = UpWithoutAuth // non-authenticated onvif command is working
+		observation.latency = dateTimeReceived.Sub(dateTimeSent)
+		return observation
+	}
+	if !isAuthError(edgexErr) {
+		// the failure of the authenticated command was not caused by the credentials, so report the underlying error
+		observation.err = dateTimeErr
 	}
-	d.lc.Debugf("%s command failed for device %s without using authentication: %s", onvif.GetSystemDateAndTime, device.Name, edgexErr.Message())
 
 	// onvif commands are not working, so let us probe it
 	if d.tcpProbe(device) {
-		return Reachable
+		observation.status = Reachable
+	} else {
+		observation.status = Unreachable
 	}
-	return Unreachable
+	return observation
 }
 
 // tcpProbe attempts to make a connection to a specific ip and port list to determine
@@ -134,8 +259,12 @@ func (d *Driver) tcpProbe(device models.Device) bool {
 
 // updateDeviceStatus updates the status of a device in the cache. Returns true if the status changed. Returns any errors that occur if failure.
 func (d *Driver) updateDeviceStatus(deviceName string, status string) (bool, error) {
-	// todo: maybe have connection levels known as ints, so that way we can log at different levels based on
-	//       if the connection level went up or down
+	return d.updateDeviceStatusAndProperties(deviceName, status, nil)
+}
+
+// updateDeviceStatusAndProperties updates the status of a device along with any additional onvif protocol properties,
+// where a nil value removes the property. Returns true if the status changed. Returns any errors that occur if failure.
+func (d *Driver) updateDeviceStatusAndProperties(deviceName string, status string, properties map[string]any) (bool, error) {
 	shouldUpdate := false
 
 	// lookup device from cache to ensure we are updating the latest version
@@ -149,15 +278,47 @@ func (d *Driver) updateDeviceStatus(deviceName string, status string) (bool, err
 	statusChanged := false
 	oldStatus := device.Protocols[OnvifProtocol][DeviceStatus]
 	if oldStatus != status {
-		d.lc.Infof("Device status for %s is now %s (used to be %s)", device.Name, status, oldStatus)
+		if statusLevel(status) < statusLevel(cast.ToString(oldStatus)) {
+			d.lc.Warnf("Device status for %s is now %s (used to be %s)", device.Name, status, oldStatus)
+		} else {
+			d.lc.Infof("Device status for %s is now %s (used to be %s)", device.Name, status, oldStatus)
+		}
 		device.Protocols[OnvifProtocol][DeviceStatus] = status
 		shouldUpdate = true
 		statusChanged = true
 	}
 
+	for key, value := range properties {
+		existing, found := device.Protocols[OnvifProtocol][key]
+		switch {
+		case value == nil && found:
+			// a nil value removes the property
+			delete(device.Protocols[OnvifProtocol], key)
+			shouldUpdate = true
+		case value != nil && existing != value:
+			device.Protocols[OnvifProtocol][key] = value
+			shouldUpdate = true
+		}
+	}
+
+	// the LastSeen is kept in memory, and only persisted along with other changes, or once the persist interval has
+	// elapsed, to avoid patching every device on every status check
 	if status != Unreachable {
-		device.Protocols[OnvifProtocol][LastSeen] = time.Now().Format(time.UnixDate)
-		shouldUpdate = true
+		now := time.Now()
+		d.health.seen(deviceName, now)
+		persistInterval, batch := d.lastSeenPersistConfig()
+		switch {
+		case shouldUpdate || persistInterval <= 0:
+			device.Protocols[OnvifProtocol][LastSeen] = now.Format(time.UnixDate)
+			shouldUpdate = true
+		case now.Sub(lastSeen(device)) < persistInterval:
+			// the LastSeen was persisted recently enough
+		case batch:
+			d.pendingLastSeen.add(deviceName)
+		default:
+			device.Protocols[OnvifProtocol][LastSeen] = now.Format(time.UnixDate)
+			shouldUpdate = true
+		}
 	}
 
 	if shouldUpdate {
@@ -170,28 +331,24 @@ func (d *Driver) updateDeviceStatus(deviceName string, status string) (bool, err
 	return statusChanged, nil
 }
 
-// taskLoop manages all of our custom background tasks such as checking camera statuses at regular intervals
+// taskLoop manages all of our custom background tasks such as checking camera statuses at regular intervals.
+// Each device is checked when its status check interval has elapsed, and the loop is woken up whenever the
+// writable configuration changes, so that changes to the intervals and EnableStatusCheck take effect immediately.
 func (d *Driver) taskLoop() {
-	d.configMu.RLock()
-	interval := d.config.AppCustom.CheckStatusInterval
-	d.configMu.RUnlock()
-	if interval > maxStatusInterval { // check the interval
-		d.lc.Warnf("Status interval of %d seconds is larger than the maximum value of %d seconds. Status interval has been set to the max value.", interval, maxStatusInterval)
-		interval = maxStatusInterval
-	}
-
-	statusTicker := time.NewTicker(time.Duration(interval) * time.Second) // TODO: Support dynamic updates for ticker interval
-
-	defer statusTicker.Stop()
-
 	d.lc.Info("Starting task loop.")
+	d.warnStatusCheckIntervals()
+
+	timer := time.NewTimer(d.checkDueStatuses())
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-d.taskCh:
 			return
-		case <-statusTicker.C:
-			d.checkStatuses() // checks the status of every device
+		case <-d.configChangedCh:
+			d.warnStatusCheckIntervals()
+		case <-timer.C:
 		}
+		timer.Reset(d.checkDueStatuses())
 	}
 }
@@ -8,11 +8,14 @@ package driver
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/dtos"
 	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -53,6 +56,21 @@ func TestUpdateDeviceStatus_noUpdate(t *testing.T) {
 	assert.False(t, changed)
 }
 
+func TestUpdateDeviceStatusAndProperties(t *testing.T) {
+	driver, mockService := createDriverWithMockService()
+	mockService.On("GetDeviceByName", testDeviceName).
+		Return(createTestDevice(), nil).Once()
+	mockService.On("PatchDevice", mock.MatchedBy(func(update dtos.UpdateDevice) bool {
+		return update.Protocols[OnvifProtocol][Address] == "192.168.1.20" &&
+			update.Protocols[OnvifProtocol][DeviceStatus] == Reachable
+	})).Return(nil).Once()
+
+	changed, err := driver.updateDeviceStatusAndProperties(testDeviceName, Reachable, map[string]any{Address: "192.168.1.20"})
+	mockService.AssertExpectations(t)
+	require.NoError(t, err)
+	assert.True(t, changed)
+}
+
 func TestDriver_TCPProbe(t *testing.T) {
 	driver, _ := createDriverWithMockService()
 	driver.config = &ServiceConfig{
@@ -130,3 +148,52 @@ func TestDriver_TCPProbe(t *testing.T) {
 		})
 	}
 }
+
+func TestDriver_checkStatusOfDevices(t *testing.T) {
+	var devices []models.Device
+	for i := 0; i < 5; i++ {
+		devices = append(devices, models.Device{
+			Name:      fmt.Sprintf("camera%d", i),
+			Protocols: map[string]models.ProtocolProperties{OnvifProtocol: {}},
+		})
+	}
+
+	tests := []struct {
+		name            string
+		workers         int
+		timeout         time.Duration
+		expectedWorkers int
+		expectedChecked int
+		expectedSkipped int
+	}{
+		{name: "bounded workers", workers: 2, timeout: time.Minute, expectedWorkers: 2, expectedChecked: 5},
+		{name: "one worker per device", timeout: time.Minute, expectedWorkers: 5, expectedChecked: 5},
+		{name: "round timed out", workers: 2, expectedWorkers: 2, expectedSkipped: 5},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			driver, mockService := createDriverWithMockService()
+			driver.macAddressMapper = NewMACAddressMapper(mockService)
+			driver.config.AppCustom.StatusCheckWorkers = test.workers
+			driver.config.AppCustom.StatusCheckJitterMillis = 10
+			mockService.On("GetDeviceByName", mock.Anything).Return(models.Device{}, errors.New("not found"))
+
+			driver.checkStatusOfDevices(devices, test.timeout)
+
+			metrics := driver.statusCheckMetrics.get()
+			require.NotNil(t, metrics.LastRound)
+			assert.Equal(t, 1, metrics.Rounds)
+			assert.Equal(t, len(devices), metrics.LastRound.Devices)
+			assert.Equal(t, test.expectedWorkers, metrics.LastRound.Workers)
+			assert.Equal(t, test.expectedChecked, metrics.LastRound.Checked)
+			assert.Equal(t, test.expectedSkipped, metrics.LastRound.Skipped)
+			assert.Equal(t, test.expectedSkipped, metrics.TotalSkipped)
+			for _, device := range devices {
+				_, checked := driver.statusSchedule.get(device.Name)
+				assert.Equal(t, test.expectedChecked > 0, checked)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1" // #nosec G505
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/IOTechSystems/onvif"
+	onvifdevice "github.com/IOTechSystems/onvif/device"
+	"github.com/IOTechSystems/onvif/gosoap"
+	"github.com/IOTechSystems/onvif/xsd"
+	xsdonvif "github.com/IOTechSystems/onvif/xsd/onvif"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+)
+
+const (
+	// clockSyncRetryInterval is the minimum time between attempts to synchronize the clock of a camera
+	clockSyncRetryInterval = time.Hour
+
+	wsPasswordDigestType = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0#PasswordDigest" // #nosec G101
+	wsNonceEncodingType  = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-soap-message-security-1.0#Base64Binary"
+)
+
+// wsSecurity is the WS-Security header of a request, in the same format as the header generated by the onvif library,
+// but with a Created timestamp which can be adjusted to the clock of the camera
+type wsSecurity struct {
+	XMLName xml.Name `xml:"http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd Security"`
+	Auth    wsUsernameToken
+}
+
+type wsUsernameToken struct {
+	XMLName  xml.Name   `xml:"UsernameToken"`
+	Username string     `xml:"Username"`
+	Password wsPassword `xml:"Password"`
+	Nonce    wsNonce    `xml:"Nonce"`
+	Created  string     `xml:"http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd Created"`
+}
+
+type wsPassword struct {
+	Type     string `xml:"Type,attr"`
+	Password string `xml:",chardata"`
+}
+
+type wsNonce struct {
+	Type  string `xml:"EncodingType,attr"`
+	Nonce string `xml:",chardata"`
+}
+
+// newWSSecurity returns a WS-UsernameToken header whose password digest is calculated using the created time
+func newWSSecurity(username string, password string, created time.Time) (wsSecurity, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return wsSecurity{}, err
+	}
+	createdStr := created.UTC().Format(time.RFC3339Nano)
+
+	// Digest = B64ENCODE( SHA1( Nonce + Created + Password ) ), as defined by the WS-UsernameToken profile
+	hasher := sha1.New() // #nosec G401
+	hasher.Write(nonce)
+	hasher.Write([]byte(createdStr + password))
+
+	return wsSecurity{
+		Auth: wsUsernameToken{
+			Username: username,
+			Password: wsPassword{Type: wsPasswordDigestType, Password: base64.StdEncoding.EncodeToString(hasher.Sum(nil))},
+			Nonce:    wsNonce{Type: wsNonceEncodingType, Nonce: base64.StdEncoding.EncodeToString(nonce)},
+			Created:  createdStr,
+		},
+	}, nil
+}
+
+// sendSoap sends the request to the camera. When the clock of the camera has drifted, the Created timestamp of the
+// WS-UsernameToken is adjusted to the time of the camera, so that the camera does not reject the credentials.
+func (onvifClient *OnvifClient) sendSoap(endpoint string, xmlRequestBody string) (*http.Response, error) {
+	offset := time.Duration(onvifClient.clockOffset.Load())
+	if offset == 0 {
+		return onvifClient.onvifDevice.SendSoap(endpoint, xmlRequestBody)
+	}
+	params := onvifClient.onvifDevice.GetDeviceParams()
+	if params.AuthMode != onvif.UsernameTokenAuth && params.AuthMode != onvif.Both {
+		return onvifClient.onvifDevice.SendSoap(endpoint, xmlRequestBody)
+	}
+
+	soap := gosoap.NewEmptySOAP()
+	soap.AddStringBodyContent(xmlRequestBody)
+	soap.AddRootNamespaces(onvif.Xlmns)
+	security, err := newWSSecurity(params.Username, params.Password, time.Now().Add(offset))
+	if err != nil {
+		return nil, fmt.Errorf("send soap request failed: %w", err)
+	}
+	header, err := xml.MarshalIndent(security, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("send soap request failed: %w", err)
+	}
+	if err = soap.AddStringHeaderContent(string(header)); err != nil {
+		return nil, fmt.Errorf("send soap request failed: %w", err)
+	}
+
+	httpClient := params.HttpClient
+	if httpClient == nil {
+		httpClient = new(http.Client)
+	}
+	if params.AuthMode == onvif.Both {
+		// the digest nonce is not shared with the onvif device, so this costs an extra round-trip while compensating
+		return onvif.NewDigestClient(httpClient, params.Username, params.Password).Do(http.MethodPost, endpoint, soap.String())
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBufferString(soap.String()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/soap+xml; charset=utf-8")
+	return httpClient.Do(req)
+}
+
+// clockSkewThreshold returns the configured clock skew above which the WS-UsernameToken of a camera is compensated,
+// or zero if compensation is disabled
+func (d *Driver) clockSkewThreshold() time.Duration {
+	d.configMu.RLock()
+	defer d.configMu.RUnlock()
+
+	return time.Duration(d.config.AppCustom.ClockSkewThresholdMillis) * time.Millisecond
+}
+
+// compensateClockSkew adjusts the Created timestamp of the requests sent to the camera when the skew of its clock
+// exceeds the ClockSkewThresholdMillis, and stops adjusting it once the clock of the camera is back in line
+func (d *Driver) compensateClockSkew(onvifClient *OnvifClient, skew time.Duration) {
+	threshold := d.clockSkewThreshold()
+	offset := time.Duration(0)
+	if threshold > 0 && skew.Abs() > threshold {
+		offset = skew
+	}
+
+	previous := time.Duration(onvifClient.clockOffset.Swap(int64(offset)))
+	switch {
+	case offset != 0 && previous == 0:
+		d.lc.Warnf("The clock of device %s is %v away from the clock of the service, the WS-UsernameToken will be adjusted to its clock.",
+			onvifClient.DeviceName, skew.Round(time.Millisecond))
+	case offset == 0 && previous != 0:
+		d.lc.Infof("The clock of device %s is back in line with the clock of the service.", onvifClient.DeviceName)
+	}
+}
+
+// syncCameraClock sets the clock of an authenticated camera whose clock has drifted beyond the ClockSkewThresholdMillis,
+// when EnableClockSync is set. The camera is configured to use the ClockSyncNTPServer if set, otherwise its UTC time
+// is set to the time of the service. Attempts are limited to one per clockSyncRetryInterval for each camera.
+func (d *Driver) syncCameraClock(onvifClient *OnvifClient, skew time.Duration) {
+	d.configMu.RLock()
+	enabled := d.config.AppCustom.EnableClockSync
+	ntpServer := d.config.AppCustom.ClockSyncNTPServer
+	threshold := time.Duration(d.config.AppCustom.ClockSkewThresholdMillis) * time.Millisecond
+	d.configMu.RUnlock()
+
+	if !enabled || threshold <= 0 || skew.Abs() <= threshold {
+		return
+	}
+	now := time.Now()
+	if lastSync := onvifClient.clockSyncedAt.Load(); lastSync != 0 && now.Sub(time.Unix(0, lastSync)) < clockSyncRetryInterval {
+		return
+	}
+	onvifClient.clockSyncedAt.Store(now.UnixNano())
+
+	if edgexErr := onvifClient.setCameraClock(ntpServer, now); edgexErr != nil {
+		d.lc.Warnf("Unable to synchronize the clock of device %s: %s", onvifClient.DeviceName, edgexErr.Error())
+		return
+	}
+	d.lc.Infof("Synchronized the clock of device %s, which was %v away from the clock of the service.",
+		onvifClient.DeviceName, skew.Round(time.Millisecond))
+}
+
+// setCameraClock configures the camera to use the ntpServer, or sets its UTC time to now if ntpServer is empty
+func (onvifClient *OnvifClient) setCameraClock(ntpServer string, now time.Time) errors.EdgeX {
+	request := onvifdevice.SetSystemDateAndTime{DaylightSavings: new(xsd.Boolean)}
+	if ntpServer != "" {
+		host := xsdonvif.NetworkHost{Type: "DNS", DNSname: xsdonvif.DNSName(ntpServer)}
+		if ip := net.ParseIP(ntpServer); ip != nil && ip.To4() != nil {
+			host = xsdonvif.NetworkHost{Type: "IPv4", IPv4Address: xsdonvif.IPv4Address(ntpServer)}
+		} else if ip != nil {
+			host = xsdonvif.NetworkHost{Type: "IPv6", IPv6Address: xsdonvif.IPv6Address(ntpServer)}
+		}
+		if edgexErr := onvifClient.callOnvifFunctionWithRequest(onvif.SetNTP, onvifdevice.SetNTP{NTPManual: host}); edgexErr != nil {
+			return errors.NewCommonEdgeXWrapper(edgexErr)
+		}
+		dateTimeType := xsdonvif.SetDateTimeType("NTP")
+		request.DateTimeType = &dateTimeType
+	} else {
+		utc := now.UTC()
+		year, month, day := xsd.Int(utc.Year()), xsd.Int(utc.Month()), xsd.Int(utc.Day())         // #nosec G115
+		hour, minute, second := xsd.Int(utc.Hour()), xsd.Int(utc.Minute()), xsd.Int(utc.Second()) // #nosec G115
+		dateTimeType := xsdonvif.SetDateTimeType("Manual")
+		request.DateTimeType = &dateTimeType
+		request.UTCDateTime = &xsdonvif.DateTimeRequest{
+			Date: &xsdonvif.DateRequest{Year: &year, Month: &month, Day: &day},
+			Time: &xsdonvif.TimeRequest{Hour: &hour, Minute: &minute, Second: &second},
+		}
+	}
+
+	return onvifClient.callOnvifFunctionWithRequest(onvif.SetSystemDateAndTime, request)
+}
+
+// callOnvifFunctionWithRequest calls a function of the device web service using the request struct
+func (onvifClient *OnvifClient) callOnvifFunctionWithRequest(functionName string, request any) errors.EdgeX {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to marshal the %s request", functionName), err)
+	}
+	if _, edgexErr := onvifClient.callOnvifFunction(onvif.DeviceWebService, functionName, data); edgexErr != nil {
+		return errors.NewCommonEdgeXWrapper(edgexErr)
+	}
+	return nil
+}
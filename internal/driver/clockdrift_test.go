@@ -0,0 +1,96 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"crypto/sha1" // #nosec G505
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/IOTechSystems/onvif"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWSSecurity(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	security, err := newWSSecurity("admin", "Password1!", created)
+	require.NoError(t, err)
+
+	assert.Equal(t, "admin", security.Auth.Username)
+	assert.Equal(t, "2026-03-01T11:00:00Z", security.Auth.Created)
+
+	nonce, err := base64.StdEncoding.DecodeString(security.Auth.Nonce.Nonce)
+	require.NoError(t, err)
+	hasher := sha1.New() // #nosec G401
+	hasher.Write(nonce)
+	hasher.Write([]byte(security.Auth.Created + "Password1!"))
+	assert.Equal(t, base64.StdEncoding.EncodeToString(hasher.Sum(nil)), security.Auth.Password.Password)
+}
+
+func TestOnvifClient_sendSoap(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		data, err := io.ReadAll(request.Body)
+		assert.NoError(t, err)
+		body = string(data)
+	}))
+	defer server.Close()
+
+	driver, _ := createDriverWithMockService()
+	client, mockDevice := createOnvifClientWithMockDevice(driver, testDeviceName)
+	mockDevice.On("GetDeviceParams").Return(onvif.DeviceParams{
+		Username:   "admin",
+		Password:   "Password1!",
+		AuthMode:   onvif.UsernameTokenAuth,
+		HttpClient: server.Client(),
+	})
+
+	// without any skew the request is sent by the onvif device
+	mockDevice.On("SendSoap", server.URL, "<tds:GetDeviceInformation/>").Return(&http.Response{}, nil).Once()
+	_, err := client.sendSoap(server.URL, "<tds:GetDeviceInformation/>")
+	require.NoError(t, err)
+
+	client.clockOffset.Store(int64(-time.Hour))
+	resp, err := client.sendSoap(server.URL, "<tds:GetDeviceInformation/>")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Contains(t, body, "<tds:GetDeviceInformation/>")
+	assert.Contains(t, body, "<Username>admin</Username>")
+
+	matches := regexp.MustCompile(`<Created[^>]*>([^<]+)</Created>`).FindStringSubmatch(body)
+	require.Len(t, matches, 2)
+	created, err := time.Parse(time.RFC3339Nano, matches[1])
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), created, time.Minute)
+	mockDevice.AssertExpectations(t)
+}
+
+func TestDriver_compensateClockSkew(t *testing.T) {
+	driver, _ := createDriverWithMockService()
+	driver.config.AppCustom.ClockSkewThresholdMillis = 5000
+	client, _ := createOnvifClientWithMockDevice(driver, testDeviceName)
+
+	driver.compensateClockSkew(client, 3*time.Second)
+	assert.Zero(t, client.clockOffset.Load())
+
+	driver.compensateClockSkew(client, -10*time.Second)
+	assert.Equal(t, int64(-10*time.Second), client.clockOffset.Load())
+
+	driver.compensateClockSkew(client, time.Second)
+	assert.Zero(t, client.clockOffset.Load())
+
+	// compensation is disabled
+	driver.config.AppCustom.ClockSkewThresholdMillis = 0
+	driver.compensateClockSkew(client, time.Hour)
+	assert.Zero(t, client.clockOffset.Load())
+}
@@ -9,6 +9,7 @@ package driver
 
 import (
 	"fmt"
+	"net"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
 	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
@@ -22,7 +23,8 @@ type CustomConfig struct {
 	RequestTimeout int
 	// DefaultSecretName indicates the secret name to retrieve username and password from secret store.
 	DefaultSecretName string
-	// DiscoveryEthernetInterface indicates the target EthernetInterface for multicast discovering.
+	// DiscoveryEthernetInterface indicates the comma separated list of target EthernetInterfaces for multicast discovering,
+	// or all to use every up and non-loopback interface.
 	DiscoveryEthernetInterface string
 	// BaseNotificationURL indicates the device service network location
 	BaseNotificationURL string
@@ -31,20 +33,136 @@ type CustomConfig struct {
 	DiscoveryMode DiscoveryMode
 	// DiscoverySubnets indicates the network segments used when discovery is scanning for devices.
 	DiscoverySubnets string
+	// DiscoveryAutoSubnetMaxSize indicates the largest subnet, as a CIDR prefix length, which is scanned
+	// for each local network interface when DiscoverySubnets is auto.
+	DiscoveryAutoSubnetMaxSize int
+	// DiscoveryTargets indicates additional network segments to scan, each with their own probe settings.
+	DiscoveryTargets map[string]DiscoveryTarget
+	// DiscoveryExclusions indicates the IP addresses, subnets and IP ranges which discovery must never probe.
+	DiscoveryExclusions string
+	// DiscoverySkipUpWithAuth indicates if netscan discovery should skip probing registered devices which are UpWithAuth.
+	DiscoverySkipUpWithAuth bool
 	// ProbeAsyncLimit indicates the maximum number of simultaneous network probes.
 	ProbeAsyncLimit int
 	// ProbeTimeoutMillis indicates the maximum amount of milliseconds to wait for each IP probe before timing out.
 	ProbeTimeoutMillis int
 	// MaxDiscoverDurationSeconds indicates the amount of seconds discovery will run before timing out.
 	MaxDiscoverDurationSeconds int
+	// MaxProbesPerSecond indicates the maximum number of network probes sent per second, or unlimited if zero.
+	MaxProbesPerSecond int
+	// ProbeJitterMillis indicates the maximum random amount of milliseconds to wait before probing each IP.
+	ProbeJitterMillis int
+	// RandomizeProbeOrder indicates if the IPs of each subnet should be probed in a random order instead of sequentially.
+	RandomizeProbeOrder bool
+	// DiscoveryWindow indicates the time of day range (HH:MM-HH:MM) outside which discovery refuses to run.
+	DiscoveryWindow DiscoveryWindow
+	// EnableHTTPDiscovery indicates if netscan discovery should also probe hosts over http for cameras
+	// which do not respond to WS-Discovery.
+	EnableHTTPDiscovery bool
+	// HTTPDiscoveryPorts indicates the comma separated list of tcp ports probed by the http netscan discovery.
+	HTTPDiscoveryPorts string
+	// DeviceNameTemplate indicates the template used to build the name of discovered cameras.
+	DeviceNameTemplate DeviceNameTemplate
+	// StableDeviceNames indicates if cameras discovered without device information should keep their name once
+	// the information is known, instead of being removed and re-added with a new name.
+	StableDeviceNames bool
+	// AutoMergeDuplicates indicates if devices registered more than once for the same camera should be merged
+	// automatically before each discovery, instead of only being reported.
+	AutoMergeDuplicates bool
 
 	// EnableStatusCheck indicates if status checking should be enabled
 	EnableStatusCheck bool
 	// CheckStatusInterval indicates the interval in seconds at which the device service will check device statuses
 	CheckStatusInterval int
+	// HealthyCheckStatusInterval indicates the interval in seconds at which devices which are UpWithAuth, and whose
+	// status is stable, are checked. CheckStatusInterval is used if zero.
+	HealthyCheckStatusInterval int
+	// FlappingCheckStatusInterval indicates the interval in seconds at which devices whose status changed recently
+	// are checked. CheckStatusInterval is used if zero.
+	FlappingCheckStatusInterval int
+	// MaxCheckStatusInterval indicates the maximum status check interval in seconds of any device.
+	MaxCheckStatusInterval int
+	// StatusCheckWorkers indicates the maximum number of devices whose status is checked simultaneously, or one per device if zero.
+	StatusCheckWorkers int
+	// StatusCheckJitterMillis indicates the maximum random amount of milliseconds to wait before checking each device.
+	StatusCheckJitterMillis int
+	// StatusDowngradeThreshold indicates the number of consecutive status checks which must find a lower status before
+	// the status of a device is lowered. Defaults to 1 if zero.
+	StatusDowngradeThreshold int
+	// StatusUpgradeThreshold indicates the number of consecutive status checks which must find a higher status before
+	// the status of a device is raised. Defaults to 1 if zero.
+	StatusUpgradeThreshold int
+	// ClockSkewThresholdMillis indicates the difference in milliseconds between the clock of a camera and the clock of
+	// the service above which the WS-UsernameToken sent to the camera is adjusted to its clock. Disabled if zero.
+	ClockSkewThresholdMillis int
+	// EnableClockSync indicates if the clock of an authenticated camera which is beyond the ClockSkewThresholdMillis
+	// should be synchronized using SetNTP or SetSystemDateAndTime
+	EnableClockSync bool
+	// ClockSyncNTPServer is the NTP server which cameras are configured to use when their clock is synchronized. If empty,
+	// the UTC time of the cameras is set to the time of the service instead.
+	ClockSyncNTPServer string
+	// EnableStatusChangeSystemEvent indicates if a system event should be published for every transition of the status
+	// of a device, in addition to the reading of its DeviceStatusChange resource
+	EnableStatusChangeSystemEvent bool
+	// LastSeenPersistInterval indicates the interval in seconds at which the LastSeen protocol property of a device is
+	// persisted when its status and properties are unchanged. The LastSeen is persisted on every status check if zero.
+	LastSeenPersistInterval int
+	// BatchLastSeenUpdates indicates if the LastSeen updates which are due should be persisted together at the end of
+	// each status check round, rather than as each device is checked
+	BatchLastSeenUpdates bool
+	// EnableDeviceRelocation indicates if the status check should try to find Unreachable devices at a new IP address
+	EnableDeviceRelocation bool
+	// StaleDevicePolicy indicates when devices which are no longer seen become Down, stale, and are removed.
+	StaleDevicePolicy StaleDevicePolicy
+	// StaleDevicePolicies are named stale device policies, which are applied to devices with a matching label, or
+	// with a matching StaleDevicePolicy property set by their provision watcher.
+	StaleDevicePolicies map[string]StaleDevicePolicy
 
 	// CredentialsMap is a map of SecretName -> Comma separated list of mac addresses
 	CredentialsMap map[string]string
+	// EnableCredentialTrial indicates if discovery should try the other credential groups against cameras which
+	// reject the credentials they are mapped to.
+	EnableCredentialTrial bool
+	// CredentialTrialMaxAttempts indicates the maximum number of credential groups tried against a camera per window.
+	CredentialTrialMaxAttempts int
+	// CredentialTrialWindowSeconds indicates the amount of seconds after which the attempts of a camera are reset.
+	CredentialTrialWindowSeconds int
+	// AuthFailureThreshold indicates the number of consecutive authenticated requests rejected by a camera after which
+	// no authenticated request is sent to it for AuthBackoffSeconds, or until its secret is updated. A camera reporting
+	// a lockout is backed off immediately. Disabled if zero.
+	AuthFailureThreshold int
+	// AuthBackoffSeconds indicates the amount of seconds during which no authenticated request is sent to a camera
+	// which reached the AuthFailureThreshold.
+	AuthBackoffSeconds int
+	// TLSCASecretName indicates the secret name to retrieve the PEM encoded CA certificates from, which are trusted
+	// in addition to the system roots when verifying the certificate of the cameras reached using https. The
+	// certificates are stored under the caBundle key.
+	TLSCASecretName string
+	// SecretRefreshWorkers indicates the maximum number of onvif clients rebuilt simultaneously after a secret update,
+	// or 8 if zero.
+	SecretRefreshWorkers int
+}
+
+// DiscoveryTarget holds the netscan settings for a single network segment
+type DiscoveryTarget struct {
+	// Subnet indicates the network segment in CIDR format.
+	Subnet string
+	// ProbeAsyncLimit overrides the global ProbeAsyncLimit for this subnet if greater than zero.
+	ProbeAsyncLimit int
+	// ProbeTimeoutMillis overrides the global ProbeTimeoutMillis for this subnet if greater than zero.
+	ProbeTimeoutMillis int
+}
+
+// StaleDevicePolicy holds the thresholds after which a device which is no longer seen is handled as gone.
+// A threshold of zero disables the step.
+type StaleDevicePolicy struct {
+	// DownAfterMissedStatusChecks indicates the consecutive Unreachable status checks after which the
+	// OperatingState of the device is set to Down.
+	DownAfterMissedStatusChecks int
+	// StaleAfterMissedDiscoveries indicates the consecutive discoveries missing the device after which it is labeled stale.
+	StaleAfterMissedDiscoveries int
+	// RemoveAfterMissedDiscoveries indicates the consecutive discoveries missing the device after which it is removed.
+	RemoveAfterMissedDiscoveries int
 }
 
 // ServiceConfig a struct that wraps CustomConfig which holds the values for driver configuration
@@ -86,7 +204,7 @@ func GetCameraXAddr(protocols map[string]models.ProtocolProperties) (string, err
 
 	xAddr := address
 	if port != "" {
-		xAddr += ":" + port
+		xAddr = net.JoinHostPort(address, port)
 	}
 
 	return xAddr, nil
@@ -37,9 +37,31 @@ const (
 	EndpointRefAddress = "EndpointRefAddress"
 	LastSeen           = "LastSeen"
 	DeviceStatus       = "DeviceStatus"
+	// XAddrs is the space separated list of all the device service addresses advertised by a camera
+	XAddrs = "XAddrs"
+	// Scheme is the scheme used to reach the device service of a camera, either http or https
+	Scheme = "Scheme"
+	// DeviceServicePath is the path of the device service of a camera, when it is not /onvif/device_service
+	DeviceServicePath = "DeviceServicePath"
+	// Scopes is the space separated list of the onvif scopes advertised by a camera
+	Scopes = "Scopes"
+	// DiscoveryInterface is the comma separated list of the ethernet interfaces a camera was discovered on via multicast
+	DiscoveryInterface = "DiscoveryInterface"
+	// CheckStatusInterval is the status check interval in seconds of a camera, overriding the configured intervals
+	CheckStatusInterval = "CheckStatusInterval"
+	// CredentialGroup is the secret name of the credential group found to work with a camera by a credential trial,
+	// which takes precedence over the CredentialsMap
+	CredentialGroup = "CredentialGroup"
+	// SecretName is the secret name of the credentials of a camera, which takes precedence over the CredentialsMap
+	SecretName = "SecretName"
+	// InsecureSkipVerify indicates if the certificate of a camera reached using https should not be verified
+	InsecureSkipVerify = "InsecureSkipVerify"
+	// CertificateFingerprint is the SHA-256 fingerprint of the certificate which a camera reached using https must present,
+	// which is trusted in place of its certificate chain
+	CertificateFingerprint = "CertificateFingerprint"
 
-	// Maximum interval for checkStatus interval
-	maxStatusInterval = 300
+	// Default maximum interval for checkStatus interval, when MaxCheckStatusInterval is not set
+	defaultMaxStatusInterval = 300
 
 	// Service is resource attribute and indicates the web service for the Onvif
 	Service = "service"
@@ -75,3 +97,10 @@ const (
 
 	UnknownDevicePrefix = "unknown_unknown_"
 )
+
+const (
+	// StaleLabel is the label added to devices which have been missing from consecutive discoveries
+	StaleLabel = "stale"
+	// StaleDevicePolicyProperty is the device property which names the stale device policy of the device
+	StaleDevicePolicyProperty = "StaleDevicePolicy"
+)
@@ -0,0 +1,341 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/IOTechSystems/onvif"
+	onvifdevice "github.com/IOTechSystems/onvif/device"
+	xsdonvif "github.com/IOTechSystems/onvif/xsd/onvif"
+	"github.com/edgexfoundry/device-sdk-go/v4/pkg/interfaces"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/labstack/echo/v4"
+	"github.com/spf13/cast"
+)
+
+const (
+	// RotateCredentials is the EdgeX function which rotates the password of a camera
+	RotateCredentials = "RotateCredentials"
+
+	CredentialsRestPath       = "credentials"
+	secretNameParam           = "secretName"
+	apiRotateCredentialsRoute = common.ApiBase + "/" + CredentialsRestPath + "/:" + secretNameParam + "/rotate"
+
+	// generatedPasswordLength is the length of the passwords generated when none is specified
+	generatedPasswordLength = 24
+	// generatedPasswordChars are the characters of the generated passwords, which exclude the symbols refused by some
+	// cameras, and the characters which are easily confused
+	generatedPasswordChars = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789"
+)
+
+// RotateCredentialsRequest is the body of a credential rotation, which generates a new password if none is specified
+type RotateCredentialsRequest struct {
+	Password string `json:",omitempty"`
+}
+
+// CredentialRotationResult is the outcome of a credential rotation for a single camera
+type CredentialRotationResult struct {
+	DeviceName string
+	// Rotated indicates the camera uses the new password
+	Rotated bool
+	// RolledBack indicates the camera was restored to the old password after the rotation failed
+	RolledBack bool   `json:",omitempty"`
+	Error      string `json:",omitempty"`
+}
+
+// CredentialRotation is the outcome of the rotation of the credentials of a secret name
+type CredentialRotation struct {
+	SecretName string
+	// Rotated indicates the new password was applied to every camera and written to the secret store
+	Rotated bool
+	Devices []CredentialRotationResult
+	Error   string `json:",omitempty"`
+}
+
+// generatePassword returns a random password containing upper case and lower case letters, and digits
+func generatePassword() (string, error) {
+	charCount := big.NewInt(int64(len(generatedPasswordChars)))
+	for {
+		password := make([]byte, generatedPasswordLength)
+		for i := range password {
+			n, err := rand.Int(rand.Reader, charCount)
+			if err != nil {
+				return "", err
+			}
+			password[i] = generatedPasswordChars[n.Int64()]
+		}
+		generated := string(password)
+		if strings.IndexFunc(generated, unicode.IsUpper) >= 0 && strings.IndexFunc(generated, unicode.IsLower) >= 0 &&
+			strings.IndexFunc(generated, unicode.IsDigit) >= 0 {
+			return generated, nil
+		}
+	}
+}
+
+// parseRotateCredentialsRequest parses the body of a credential rotation, which may be empty
+func parseRotateCredentialsRequest(data []byte) (RotateCredentialsRequest, errors.EdgeX) {
+	request := RotateCredentialsRequest{}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return request, nil
+	}
+	if err := json.Unmarshal(data, &request); err != nil {
+		return request, errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to parse the credential rotation request", err)
+	}
+	return request, nil
+}
+
+// devicesUsingSecret returns the registered devices whose credentials are looked up using the secret name
+func (d *Driver) devicesUsingSecret(secretName string) []models.Device {
+	var devices []models.Device
+	for _, device := range d.sdkService.Devices() {
+		if _, ok := device.Protocols[OnvifProtocol]; !ok {
+			continue
+		}
+		if d.secretNameForDevice(device) == secretName {
+			devices = append(devices, device)
+		}
+	}
+	return devices
+}
+
+// rotateDeviceCredentials rotates the password of a single camera. The secret name of the camera must not be shared
+// with other devices, since they would no longer be able to authenticate.
+func (d *Driver) rotateDeviceCredentials(device models.Device, password string) (CredentialRotation, errors.EdgeX) {
+	secretName := d.secretNameForDevice(device)
+	var others []string
+	for _, other := range d.devicesUsingSecret(secretName) {
+		if other.Name != device.Name {
+			others = append(others, other.Name)
+		}
+	}
+	if len(others) > 0 {
+		return CredentialRotation{SecretName: secretName}, errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("the secret name %s of the device %s is shared with the devices %v, rotate the credentials of the group via %s",
+				secretName, device.Name, others, apiRotateCredentialsRoute), nil)
+	}
+	return d.rotateCredentials(secretName, []models.Device{device}, password)
+}
+
+// rotateCredentials applies a new password to every camera using the secret name, verifying each of them with an
+// authenticated GetDeviceInformation, and then writes it to the secret store. The cameras are restored to the old
+// password if any of them, or the secret store, fails, so that the cameras and the secret store never disagree.
+func (d *Driver) rotateCredentials(secretName string, devices []models.Device, password string) (CredentialRotation, errors.EdgeX) {
+	d.credentialRotationMu.Lock()
+	defer d.credentialRotationMu.Unlock()
+
+	rotation := CredentialRotation{SecretName: secretName, Devices: []CredentialRotationResult{}}
+	if strings.ToLower(secretName) == noAuthSecretName {
+		return rotation, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("the secret name %s has no credentials to rotate", secretName), nil)
+	}
+	if len(devices) == 0 {
+		return rotation, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("no devices use the secret name %s", secretName), nil)
+	}
+
+	oldCredentials, edgexErr := d.tryGetCredentialsInternal(secretName)
+	if edgexErr != nil {
+		return rotation, errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to load the credentials of the secret name %s", secretName), edgexErr)
+	}
+	if oldCredentials.AuthMode == AuthModeNone || oldCredentials.Username == "" {
+		return rotation, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("the secret name %s has no credentials to rotate", secretName), nil)
+	}
+	if password == "" {
+		var err error
+		if password, err = generatePassword(); err != nil {
+			return rotation, errors.NewCommonEdgeX(errors.KindServerError, "failed to generate a password", err)
+		}
+	} else if password == oldCredentials.Password {
+		return rotation, errors.NewCommonEdgeX(errors.KindContractInvalid, "the new password must differ from the current password", nil)
+	}
+	newCredentials := oldCredentials
+	newCredentials.Password = password
+
+	// every camera must currently authenticate, otherwise it would be left behind using the old password
+	clients := make([]*OnvifClient, len(devices))
+	var notReady []string
+	for i, device := range devices {
+		if cast.ToString(device.Protocols[OnvifProtocol][DeviceStatus]) != UpWithAuth {
+			notReady = append(notReady, device.Name)
+			continue
+		}
+		client, edgexErr := d.getOrCreateOnvifClient(device)
+		if edgexErr != nil {
+			notReady = append(notReady, device.Name)
+			continue
+		}
+		clients[i] = client
+	}
+	if len(notReady) > 0 {
+		return rotation, errors.NewCommonEdgeX(errors.KindStatusConflict,
+			fmt.Sprintf("the devices %v are not %s, rotating the credentials of the secret name %s would lock them out", notReady, UpWithAuth, secretName), nil)
+	}
+
+	for i, device := range devices {
+		rotation.Devices = append(rotation.Devices, CredentialRotationResult{DeviceName: device.Name})
+		if edgexErr = d.rotateCameraPassword(device, clients[i], newCredentials); edgexErr != nil {
+			rotation.Devices[i].Error = edgexErr.Error()
+			d.rollbackCredentialRotation(&rotation, devices, clients, oldCredentials, newCredentials)
+			return rotation, errors.NewCommonEdgeX(errors.KindServerError,
+				fmt.Sprintf("failed to rotate the password of the device %s, the rotation was rolled back", device.Name), edgexErr)
+		}
+		rotation.Devices[i].Rotated = true
+	}
+
+	secrets := map[string]string{
+		UsernameKey: newCredentials.Username,
+		PasswordKey: newCredentials.Password,
+		AuthModeKey: newCredentials.AuthMode,
+	}
+	if err := d.sdkService.SecretProvider().StoreSecret(secretName, secrets); err != nil {
+		d.rollbackCredentialRotation(&rotation, devices, clients, oldCredentials, newCredentials)
+		return rotation, errors.NewCommonEdgeX(errors.KindServerError,
+			fmt.Sprintf("failed to store the credentials of the secret name %s, the rotation was rolled back", secretName), err)
+	}
+	rotation.Rotated = true
+	d.lc.Infof("Rotated the password of the secret name %s on %d camera(s)", secretName, len(devices))
+
+	d.refreshOnvifClients(devices)
+	return rotation, nil
+}
+
+// rotateCameraPassword sets the new password of the user of a camera, and verifies the camera accepts it
+func (d *Driver) rotateCameraPassword(device models.Device, client *OnvifClient, newCredentials Credentials) errors.EdgeX {
+	if edgexErr := client.setUserPassword(newCredentials.Username, newCredentials.Password); edgexErr != nil {
+		return errors.NewCommonEdgeXWrapper(edgexErr)
+	}
+	verifier, edgexErr := d.newCredentialRotationClient(device, client, newCredentials)
+	if edgexErr != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, "failed to connect using the new password", edgexErr)
+	}
+	if _, edgexErr = verifier.getDeviceInformation(device); edgexErr != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, "failed to verify the new password", edgexErr)
+	}
+	return nil
+}
+
+// rollbackCredentialRotation restores the old password of the cameras which the rotation was attempted on
+func (d *Driver) rollbackCredentialRotation(rotation *CredentialRotation, devices []models.Device, clients []*OnvifClient,
+	oldCredentials Credentials, newCredentials Credentials) {
+	for i := range rotation.Devices {
+		rotation.Devices[i].Rotated = false
+		rotation.Devices[i].RolledBack = d.restoreCameraPassword(devices[i], clients[i], oldCredentials, newCredentials)
+	}
+}
+
+// restoreCameraPassword sets the old password of the user of a camera. It is first attempted using the new password,
+// and then the old password in case the camera never applied the new one.
+func (d *Driver) restoreCameraPassword(device models.Device, client *OnvifClient, oldCredentials Credentials, newCredentials Credentials) bool {
+	verifier, edgexErr := d.newCredentialRotationClient(device, client, newCredentials)
+	if edgexErr == nil {
+		if edgexErr = verifier.setUserPassword(oldCredentials.Username, oldCredentials.Password); edgexErr == nil {
+			return true
+		}
+	}
+	if err := client.setUserPassword(oldCredentials.Username, oldCredentials.Password); err != nil {
+		d.lc.Errorf("Failed to restore the old password of the device %s, its credentials must be fixed manually: %s, %s",
+			device.Name, edgexErr.Error(), err.Error())
+		return false
+	}
+	return true
+}
+
+// newCredentialRotationClient creates a temporary OnvifClient for the camera of the client, using the credentials
+func (d *Driver) newCredentialRotationClient(device models.Device, client *OnvifClient, credentials Credentials) (*OnvifClient, errors.EdgeX) {
+	xAddr, edgexErr := GetCameraXAddr(device.Protocols)
+	if edgexErr != nil {
+		return nil, errors.NewCommonEdgeXWrapper(edgexErr)
+	}
+	onvifDevice, err := d.newOnvifDevice(xAddr, device.Protocols, credentials, client.tlsSettings)
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindServiceUnavailable, "failed to initialize Onvif device client", err)
+	}
+	verifier := &OnvifClient{
+		driver:      d,
+		lc:          d.lc,
+		DeviceName:  device.Name,
+		onvifDevice: onvifDevice,
+		serviceURL:  client.serviceURL,
+		tlsSettings: client.tlsSettings,
+	}
+	verifier.clockOffset.Store(client.clockOffset.Load())
+	return verifier, nil
+}
+
+// setUserPassword sets the password of an existing user of the camera, keeping its user level
+func (onvifClient *OnvifClient) setUserPassword(username string, password string) errors.EdgeX {
+	response, edgexErr := onvifClient.callOnvifFunction(onvif.DeviceWebService, onvif.GetUsers, nil)
+	if edgexErr != nil {
+		return errors.NewCommonEdgeXWrapper(edgexErr)
+	}
+	users, ok := response.(*onvifdevice.GetUsersResponse)
+	if !ok {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("invalid GetUsersResponse of type %T for the camera %s", response, onvifClient.DeviceName), nil)
+	}
+
+	var userLevel *xsdonvif.UserLevel
+	for _, user := range users.User {
+		if user.Username == username {
+			userLevel = user.UserLevel
+			break
+		}
+	}
+	if userLevel == nil {
+		return errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("the user %s does not exist on the camera %s", username, onvifClient.DeviceName), nil)
+	}
+
+	request := onvifdevice.SetUser{User: []xsdonvif.UserRequest{{Username: username, Password: password, UserLevel: userLevel}}}
+	return onvifClient.callOnvifFunctionWithRequest(onvif.SetUser, request)
+}
+
+// addCredentialRoutes adds the route for rotating the credentials of every camera using a secret name
+func (d *Driver) addCredentialRoutes() errors.EdgeX {
+	if err := d.sdkService.AddCustomRoute(apiRotateCredentialsRoute, interfaces.Authenticated, d.postRotateCredentials, http.MethodPost); err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("unable to add required route: %s: %s", apiRotateCredentialsRoute, err.Error()), err)
+	}
+	d.lc.Infof("Route %s added.", apiRotateCredentialsRoute)
+	return nil
+}
+
+// postRotateCredentials rotates the password of every camera using the secret name, which must be a group of the
+// CredentialsMap or the DefaultSecretName, and returns the outcome of each camera
+func (d *Driver) postRotateCredentials(c echo.Context) error {
+	secretName := c.Param(secretNameParam)
+	d.configMu.RLock()
+	_, mapped := d.config.AppCustom.CredentialsMap[secretName]
+	isDefault := secretName == d.config.AppCustom.DefaultSecretName
+	d.configMu.RUnlock()
+	if !mapped && !isDefault {
+		return c.String(http.StatusNotFound, fmt.Sprintf("the secret name %s is not a credential group", secretName))
+	}
+
+	data, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	request, edgexErr := parseRotateCredentialsRequest(data)
+	if edgexErr != nil {
+		return c.String(edgexErr.Code(), edgexErr.Error())
+	}
+
+	rotation, edgexErr := d.rotateCredentials(secretName, d.devicesUsingSecret(secretName), request.Password)
+	if edgexErr != nil {
+		d.lc.Errorf("Failed to rotate the credentials of the secret name %s: %s", secretName, edgexErr.Error())
+		rotation.Error = edgexErr.Error()
+		return c.JSON(edgexErr.Code(), rotation)
+	}
+	return c.JSON(http.StatusOK, rotation)
+}
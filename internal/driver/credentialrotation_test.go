@@ -0,0 +1,230 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"crypto/sha1" // #nosec G505
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"unicode"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v4/bootstrap/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const soapEnvelopeFormat = `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"><s:Body>%s</s:Body></s:Envelope>`
+
+var (
+	wsNoncePattern       = regexp.MustCompile(`<Nonce[^>]*>([^<]+)</Nonce>`)
+	wsCreatedPattern     = regexp.MustCompile(`<Created[^>]*>([^<]+)</Created>`)
+	wsPasswordPattern    = regexp.MustCompile(`<Password[^>]*>([^<]+)</Password>`)
+	setUserPasswordRegex = regexp.MustCompile(`<onvif:Password>([^<]*)</onvif:Password>`)
+)
+
+// fakeCamera is an onvif device service which authenticates the WS-UsernameToken of the admin user
+type fakeCamera struct {
+	mu       sync.Mutex
+	password string
+	// ignoreSetUser makes the camera accept SetUser requests without changing the password
+	ignoreSetUser bool
+	// rejected is the number of requests which failed to authenticate
+	rejected int
+	server   *httptest.Server
+}
+
+func newFakeCamera(t *testing.T, password string) *fakeCamera {
+	camera := &fakeCamera{password: password}
+	camera.server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		data, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		body := string(data)
+
+		camera.mu.Lock()
+		defer camera.mu.Unlock()
+		if strings.Contains(body, "GetCapabilities") {
+			return
+		}
+		if strings.Contains(body, "GetSystemDateAndTime") {
+			_, _ = fmt.Fprintf(writer, soapEnvelopeFormat, "<GetSystemDateAndTimeResponse/>")
+			return
+		}
+		if !camera.authenticated(body) {
+			camera.rejected++
+			writer.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprintf(writer, soapEnvelopeFormat, "<s:Fault><s:Reason><s:Text>Sender not Authorized</s:Text></s:Reason></s:Fault>")
+			return
+		}
+
+		content := ""
+		switch {
+		case strings.Contains(body, "GetUsers"):
+			content = "<GetUsersResponse><User><Username>admin</Username><UserLevel>Administrator</UserLevel></User></GetUsersResponse>"
+		case strings.Contains(body, "SetUser"):
+			if matches := setUserPasswordRegex.FindStringSubmatch(body); len(matches) == 2 && !camera.ignoreSetUser {
+				camera.password = matches[1]
+			}
+			content = "<SetUserResponse/>"
+		case strings.Contains(body, "GetDeviceInformation"):
+			content = "<GetDeviceInformationResponse><Manufacturer>Fake</Manufacturer></GetDeviceInformationResponse>"
+		}
+		_, _ = fmt.Fprintf(writer, soapEnvelopeFormat, content)
+	}))
+	t.Cleanup(camera.server.Close)
+	return camera
+}
+
+// authenticated verifies the password digest of the WS-UsernameToken of the request
+func (camera *fakeCamera) authenticated(body string) bool {
+	nonceMatches := wsNoncePattern.FindStringSubmatch(body)
+	createdMatches := wsCreatedPattern.FindStringSubmatch(body)
+	passwordMatches := wsPasswordPattern.FindStringSubmatch(body)
+	if len(nonceMatches) != 2 || len(createdMatches) != 2 || len(passwordMatches) != 2 {
+		return false
+	}
+	nonce, err := base64.StdEncoding.DecodeString(nonceMatches[1])
+	if err != nil {
+		return false
+	}
+	hasher := sha1.New() // #nosec G401
+	hasher.Write(nonce)
+	hasher.Write([]byte(createdMatches[1] + camera.password))
+	return base64.StdEncoding.EncodeToString(hasher.Sum(nil)) == passwordMatches[1]
+}
+
+func (camera *fakeCamera) currentPassword() string {
+	camera.mu.Lock()
+	defer camera.mu.Unlock()
+	return camera.password
+}
+
+func (camera *fakeCamera) rejectedRequests() int {
+	camera.mu.Lock()
+	defer camera.mu.Unlock()
+	return camera.rejected
+}
+
+func (camera *fakeCamera) device(name string) models.Device {
+	host, port, _ := net.SplitHostPort(camera.server.Listener.Addr().String())
+	return models.Device{Name: name, Protocols: map[string]models.ProtocolProperties{
+		OnvifProtocol: {Address: host, Port: port, DeviceStatus: UpWithAuth},
+	}}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	password, err := generatePassword()
+	require.NoError(t, err)
+	assert.Len(t, password, generatedPasswordLength)
+	assert.True(t, strings.IndexFunc(password, unicode.IsUpper) >= 0)
+	assert.True(t, strings.IndexFunc(password, unicode.IsLower) >= 0)
+	assert.True(t, strings.IndexFunc(password, unicode.IsDigit) >= 0)
+
+	other, err := generatePassword()
+	require.NoError(t, err)
+	assert.NotEqual(t, password, other)
+}
+
+func TestDriver_rotateCredentials(t *testing.T) {
+	const secretName = "group"
+	const oldPassword = "OldPassword1"
+	const newPassword = "NewPassword2"
+
+	tests := []struct {
+		name             string
+		ignoreSetUser    bool
+		unreachable      bool
+		storeErr         error
+		expectedKind     errors.ErrKind
+		expectedPassword string
+		expectStore      bool
+	}{
+		{name: "rotated", expectedPassword: newPassword, expectStore: true},
+		{name: "verification failed", ignoreSetUser: true, expectedKind: errors.KindServerError, expectedPassword: oldPassword},
+		{name: "secret store failed", storeErr: fmt.Errorf("secret store unavailable"), expectedKind: errors.KindServerError,
+			expectedPassword: oldPassword, expectStore: true},
+		{name: "camera not UpWithAuth", unreachable: true, expectedKind: errors.KindStatusConflict, expectedPassword: oldPassword},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			driver, mockService := createDriverWithMockService()
+			driver.config.AppCustom.DefaultSecretName = secretName
+			mockSecretProvider := &mocks.SecretProvider{}
+			mockSecretProvider.On("GetSecret", secretName, UsernameKey, PasswordKey, AuthModeKey).
+				Return(map[string]string{UsernameKey: "admin", PasswordKey: oldPassword, AuthModeKey: AuthModeUsernameToken}, nil)
+			if test.expectStore {
+				mockSecretProvider.On("StoreSecret", secretName, map[string]string{
+					UsernameKey: "admin", PasswordKey: newPassword, AuthModeKey: AuthModeUsernameToken,
+				}).Return(test.storeErr).Once()
+			}
+			mockService.On("SecretProvider").Return(mockSecretProvider)
+
+			cameras := []*fakeCamera{newFakeCamera(t, oldPassword), newFakeCamera(t, oldPassword)}
+			cameras[1].ignoreSetUser = test.ignoreSetUser
+			var devices []models.Device
+			for i, camera := range cameras {
+				device := camera.device(fmt.Sprintf("camera%d", i))
+				client, edgexErr := driver.newTemporaryOnvifClient(device)
+				require.NoError(t, edgexErr)
+				driver.onvifClients[device.Name] = client
+				devices = append(devices, device)
+			}
+			if test.unreachable {
+				devices[1].Protocols[OnvifProtocol][DeviceStatus] = Unreachable
+			}
+
+			rotation, edgexErr := driver.rotateCredentials(secretName, devices, newPassword)
+			mockSecretProvider.AssertExpectations(t)
+			if !test.expectStore {
+				mockSecretProvider.AssertNotCalled(t, "StoreSecret", mock.Anything, mock.Anything)
+			}
+			for _, camera := range cameras {
+				assert.Equal(t, test.expectedPassword, camera.currentPassword())
+			}
+			if test.expectedKind != "" {
+				require.Error(t, edgexErr)
+				assert.Equal(t, test.expectedKind, errors.Kind(edgexErr))
+				assert.False(t, rotation.Rotated)
+				for _, result := range rotation.Devices {
+					assert.False(t, result.Rotated)
+					assert.True(t, result.RolledBack)
+				}
+				return
+			}
+			require.NoError(t, edgexErr)
+			assert.True(t, rotation.Rotated)
+			require.Len(t, rotation.Devices, len(devices))
+			for _, result := range rotation.Devices {
+				assert.True(t, result.Rotated)
+			}
+		})
+	}
+}
+
+func TestDriver_rotateDeviceCredentials_sharedSecret(t *testing.T) {
+	driver, mockService := createDriverWithMockService()
+	driver.config.AppCustom.DefaultSecretName = "group"
+	device := createTestDeviceWithProtocols(map[string]models.ProtocolProperties{OnvifProtocol: {DeviceStatus: UpWithAuth}})
+	other := models.Device{Name: "other", Protocols: map[string]models.ProtocolProperties{OnvifProtocol: {}}}
+	mockService.On("Devices").Return([]models.Device{device, other})
+
+	_, edgexErr := driver.rotateDeviceCredentials(device, "NewPassword2")
+	require.Error(t, edgexErr)
+	assert.Equal(t, errors.KindContractInvalid, errors.Kind(edgexErr))
+}
@@ -8,6 +8,7 @@
 package driver
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/IOTechSystems/onvif"
@@ -91,42 +92,76 @@ func (d *Driver) tryGetCredentialsInternal(secretName string) (Credentials, erro
 // does not exist in the Secret Store, noAuthCredentials are returned, allowing the user
 // to still call unauthenticated endpoints.
 func (d *Driver) getCredentialsForDevice(device models.Device) Credentials {
+	secretName := d.secretNameForDevice(device)
+	credentials, edgexErr := d.tryGetCredentialsInternal(secretName)
+	if edgexErr != nil {
+		// if credentials are not found, instead of returning an error, set the AuthMode to NoAuth
+		// and allow the user to call unauthenticated endpoints
+		d.lc.Errorf("Failed to retrieve credentials for the secret name %s. Falling back to using NoAuth: %s", secretName, edgexErr.Error())
+		return noAuthCredentials
+	}
+
+	return credentials
+}
+
+// secretNameForDevice returns the secret name referenced by the device's SecretName protocol property, otherwise the
+// credential group found by a credential trial, the secret name mapped to the device's MAC address, or the default
+// secret name
+func (d *Driver) secretNameForDevice(device models.Device) string {
+	if secretName := cast.ToString(device.Protocols[OnvifProtocol][SecretName]); secretName != "" {
+		return secretName
+	}
+	// the credential group is also needed by cameras whose MAC address is unknown, which cannot be mapped to it
+	if secretName := cast.ToString(device.Protocols[OnvifProtocol][CredentialGroup]); secretName != "" {
+		return secretName
+	}
+
 	d.configMu.RLock()
 	defaultSecretName := d.config.AppCustom.DefaultSecretName
 	d.configMu.RUnlock()
 
-	secretName := defaultSecretName
 	macAddress := ""
 	if v, ok := device.Protocols[OnvifProtocol][MACAddress]; ok {
 		macAddress = cast.ToString(v)
 	}
-	if macAddress != "" {
-		secretName = d.macAddressMapper.TryGetSecretNameForMACAddress(macAddress, defaultSecretName)
-	} else {
+	if macAddress == "" {
 		d.lc.Warnf("Device %s is missing MAC Address, using default secret name", device.Name)
+		return defaultSecretName
 	}
-
-	credentials, edgexErr := d.tryGetCredentialsInternal(secretName)
-	if edgexErr != nil {
-		// if credentials are not found, instead of returning an error, set the AuthMode to NoAuth
-		// and allow the user to call unauthenticated endpoints
-		d.lc.Errorf("Failed to retrieve credentials for the secret name %s. Falling back to using NoAuth: %s", secretName, edgexErr.Error())
-		return noAuthCredentials
-	}
-
-	return credentials
+	return d.macAddressMapper.TryGetSecretNameForMACAddress(macAddress, defaultSecretName)
 }
 
+// secretUpdated rebuilds the onvif clients of the devices which depend on the updated secret name
 func (d *Driver) secretUpdated(secretName string) {
 	d.lc.Infof("Secret updated callback called for secretName '%s'", secretName)
 
-	for _, device := range d.sdkService.Devices() {
-		d.lc.Tracef("Updating onvif client for device %s", device.Name)
-		err := d.updateOnvifClient(device)
+	deviceNames := d.secretIndex.devicesUsing(secretName)
+	// the new secret may fix the credentials which the cameras rejected
+	d.resetAuthBackoff(deviceNames...)
+
+	var devices []models.Device
+	for _, deviceName := range deviceNames {
+		device, err := d.sdkService.GetDeviceByName(deviceName)
 		if err != nil {
-			d.lc.Errorf("Unable to update onvif client for device: %s, %v", device.Name, err)
+			d.lc.Warnf("Unable to update onvif client for device: %s, %v", deviceName, err)
+			continue
 		}
+		devices = append(devices, device)
 	}
+	d.refreshOnvifClients(devices)
 
-	d.lc.Trace("Done updating onvif clients")
+	d.lc.Tracef("Done updating %d onvif clients", len(devices))
+}
+
+// validateSecretName verifies that the secret name referenced by the SecretName protocol property of a device, if any,
+// exists in the secret store
+func (d *Driver) validateSecretName(protocols map[string]models.ProtocolProperties) errors.EdgeX {
+	secretName := cast.ToString(protocols[OnvifProtocol][SecretName])
+	if secretName == "" {
+		return nil
+	}
+	if _, edgexErr := d.tryGetCredentialsInternal(secretName); edgexErr != nil {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("unable to load the credentials of the %s %s", SecretName, secretName), edgexErr)
+	}
+	return nil
 }
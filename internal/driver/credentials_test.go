@@ -171,10 +171,12 @@ func TestGetCredentialsForDevice(t *testing.T) {
 	}
 
 	tests := []struct {
-		name        string
-		macAddress  string
-		secretStore map[string]Credentials
-		expected    Credentials
+		name            string
+		macAddress      string
+		secretName      string
+		credentialGroup string
+		secretStore     map[string]Credentials
+		expected        Credentials
 	}{
 		{
 			name:        "missing MAC, fallback to default credentials",
@@ -212,6 +214,49 @@ func TestGetCredentialsForDevice(t *testing.T) {
 			secretStore: map[string]Credentials{},
 			expected:    noAuthCredentials,
 		},
+		{
+			name:        "secret name takes precedence over the MAC mapping",
+			macAddress:  testMACAddress,
+			secretName:  defaultSecretName,
+			secretStore: existingSecrets,
+			expected:    existingSecrets[defaultSecretName],
+		},
+		{
+			name:        "secret name without MAC",
+			macAddress:  "",
+			secretName:  secret1Name,
+			secretStore: existingSecrets,
+			expected:    existingSecrets[secret1Name],
+		},
+		{
+			name:            "credential group without MAC",
+			macAddress:      "",
+			credentialGroup: secret1Name,
+			secretStore:     existingSecrets,
+			expected:        existingSecrets[secret1Name],
+		},
+		{
+			name:            "credential group takes precedence over the MAC mapping",
+			macAddress:      bogusMAC,
+			credentialGroup: secret1Name,
+			secretStore:     existingSecrets,
+			expected:        existingSecrets[secret1Name],
+		},
+		{
+			name:            "secret name takes precedence over the credential group",
+			macAddress:      "",
+			secretName:      defaultSecretName,
+			credentialGroup: secret1Name,
+			secretStore:     existingSecrets,
+			expected:        existingSecrets[defaultSecretName],
+		},
+		{
+			name:        "secret name points to missing secret, fallback to no auth",
+			macAddress:  testMACAddress,
+			secretName:  "missing",
+			secretStore: existingSecrets,
+			expected:    noAuthCredentials,
+		},
 	}
 
 	driver, mockService := createDriverWithMockService()
@@ -246,7 +291,9 @@ func TestGetCredentialsForDevice(t *testing.T) {
 
 			device := createTestDeviceWithProtocols(map[string]models.ProtocolProperties{
 				OnvifProtocol: {
-					MACAddress: test.macAddress,
+					MACAddress:      test.macAddress,
+					SecretName:      test.secretName,
+					CredentialGroup: test.credentialGroup,
 				},
 			})
 
@@ -255,3 +302,39 @@ func TestGetCredentialsForDevice(t *testing.T) {
 		})
 	}
 }
+
+func TestDriver_ValidateDevice_secretName(t *testing.T) {
+	tests := []struct {
+		name          string
+		secretName    string
+		errorExpected bool
+	}{
+		{name: "no secret name"},
+		{name: "existing secret name", secretName: secret1Name},
+		{name: "no auth", secretName: "NoAuth"},
+		{name: "missing secret name", secretName: "missing", errorExpected: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			driver, mockService := createDriverWithMockService()
+			mockSecretProvider := &mocks.SecretProvider{}
+			mockSecretProvider.On("GetSecret", secret1Name, UsernameKey, PasswordKey, AuthModeKey).
+				Return(map[string]string{UsernameKey: "user1", PasswordKey: "pass1", AuthModeKey: AuthModeDigest}, nil)
+			mockSecretProvider.On("GetSecret", "missing", UsernameKey, PasswordKey, AuthModeKey).
+				Return(nil, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "secret missing does not exist", nil))
+			mockService.On("SecretProvider").Return(mockSecretProvider)
+
+			device := createTestDeviceWithProtocols(map[string]models.ProtocolProperties{
+				OnvifProtocol: {Address: "192.168.1.10", Port: "80", SecretName: test.secretName},
+			})
+			err := driver.ValidateDevice(device)
+			if test.errorExpected {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
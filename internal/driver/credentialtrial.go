@@ -0,0 +1,218 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	onvifdevice "github.com/IOTechSystems/onvif/device"
+	"github.com/IOTechSystems/onvif/gosoap"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/spf13/cast"
+)
+
+// credentialTrialTracker keeps track of the number of credential groups tried against each camera, so that
+// cameras which lock out a user after repeated failed logins are not tried more than the attempt budget allows
+type credentialTrialTracker struct {
+	mu sync.Mutex
+	// cameras is a map of EndpointRefAddress to the attempts made against the camera
+	cameras map[string]*credentialTrialAttempts
+}
+
+// credentialTrialAttempts is the number of failed attempts made against a camera since the start of the window
+type credentialTrialAttempts struct {
+	count       int
+	windowStart time.Time
+}
+
+// reserve counts an attempt against the camera, and returns false if the camera has no attempts left in the current window
+func (t *credentialTrialTracker) reserve(endpointRef string, maxAttempts int, window time.Duration, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.cameras == nil {
+		t.cameras = make(map[string]*credentialTrialAttempts)
+	}
+	attempts, found := t.cameras[endpointRef]
+	if !found || now.Sub(attempts.windowStart) >= window {
+		attempts = &credentialTrialAttempts{windowStart: now}
+		t.cameras[endpointRef] = attempts
+	}
+	if attempts.count >= maxAttempts {
+		return false
+	}
+	attempts.count++
+	return true
+}
+
+// exhaust uses up the remaining attempts of the camera for the current window, such as when the camera reports a lockout
+func (t *credentialTrialTracker) exhaust(endpointRef string, maxAttempts int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if attempts, found := t.cameras[endpointRef]; found {
+		attempts.count = maxAttempts
+	}
+}
+
+// reset forgets the attempts made against the camera, once a credential group has succeeded
+func (t *credentialTrialTracker) reset(endpointRef string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.cameras, endpointRef)
+}
+
+// notAuthorizedSubcode is the SOAP fault subcode, without its namespace prefix, of the requests rejected by a camera
+// because of their credentials, such as ter:NotAuthorized
+const notAuthorizedSubcode = "NotAuthorized"
+
+// lockoutPattern matches the messages of the errors reported by cameras which locked out the user, such as "locked out"
+// or "the user account is locked", but not "clock" or "unlocked"
+var lockoutPattern = regexp.MustCompile(`\block(ed)?[ -]?out\b|\b(account|user) (is |has been )?locked\b`)
+
+// isNotAuthorizedFault returns true if any subcode of the SOAP fault is NotAuthorized
+func isNotAuthorizedFault(fault *gosoap.SOAPFault) bool {
+	if fault == nil {
+		return false
+	}
+	for subcode := &fault.Code.Subcode; subcode != nil; subcode = subcode.Subcode {
+		value := subcode.Value
+		if i := strings.LastIndex(value, ":"); i >= 0 {
+			value = value[i+1:]
+		}
+		if strings.EqualFold(strings.TrimSpace(value), notAuthorizedSubcode) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAuthError returns true if the error was caused by the camera rejecting the credentials. The onvif client reports
+// these with KindInvalidId, from the HTTP 401 status or the NotAuthorized fault subcode. The errors of the onvif
+// library, which are not classified, are matched by the fault subcode in their message.
+func isAuthError(err errors.EdgeX) bool {
+	if errors.Kind(err) == errors.KindInvalidId {
+		return true
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "ter:"+strings.ToLower(notAuthorizedSubcode))
+}
+
+// isLockoutError returns true if the error indicates that the camera has locked out the user. The onvif client reports
+// these with KindServiceLocked, from the HTTP 423 or 429 status. Otherwise, the message of the error is matched
+// against the wordings used by cameras which locked out the user.
+func isLockoutError(err errors.EdgeX) bool {
+	if errors.Kind(err) == errors.KindServiceLocked {
+		return true
+	}
+	return lockoutPattern.MatchString(strings.ToLower(err.Error()))
+}
+
+// credentialTrialSecretNames returns the secret names to try against a camera which failed to authenticate using
+// failedSecretName. These are the secret names of the CredentialsMap, and the default secret name, in sorted order.
+func credentialTrialSecretNames(credentialsMap map[string]string, defaultSecretName string, failedSecretName string) []string {
+	var secretNames []string
+	for secretName := range credentialsMap {
+		secretNames = append(secretNames, secretName)
+	}
+	if defaultSecretName != "" && !slices.Contains(secretNames, defaultSecretName) {
+		secretNames = append(secretNames, defaultSecretName)
+	}
+	slices.Sort(secretNames)
+	return slices.DeleteFunc(secretNames, func(secretName string) bool {
+		return secretName == failedSecretName || strings.ToLower(secretName) == noAuthSecretName
+	})
+}
+
+// tryCredentialGroups is called when a discovered camera fails to authenticate using the credentials it is mapped to.
+// Each of the other credential groups is tried until one succeeds, within the attempt budget of the camera. The trial
+// stops as soon as the camera reports that the user is locked out. On success, the client using the working
+// credentials is returned along with the device information and the secret name of the credential group.
+func (d *Driver) tryCredentialGroups(device models.Device) (*OnvifClient, *onvifdevice.GetDeviceInformationResponse, string, bool) {
+	failedSecretName := d.secretNameForDevice(device)
+	d.configMu.RLock()
+	maxAttempts := d.config.AppCustom.CredentialTrialMaxAttempts
+	window := time.Duration(d.config.AppCustom.CredentialTrialWindowSeconds) * time.Second
+	secretNames := credentialTrialSecretNames(d.config.AppCustom.CredentialsMap, d.config.AppCustom.DefaultSecretName, failedSecretName)
+	d.configMu.RUnlock()
+
+	endpointRef := cast.ToString(device.Protocols[OnvifProtocol][EndpointRefAddress])
+	xAddr, edgexErr := GetCameraXAddr(device.Protocols)
+	if edgexErr != nil {
+		d.lc.Warnf("Unable to try the credential groups for the camera %s: %s", endpointRef, edgexErr.Error())
+		return nil, nil, "", false
+	}
+	tlsSettings, edgexErr := d.tlsSettingsForDevice(device.Protocols)
+	if edgexErr != nil {
+		d.lc.Warnf("Unable to try the credential groups for the camera %s: %s", endpointRef, edgexErr.Error())
+		return nil, nil, "", false
+	}
+
+	for _, secretName := range secretNames {
+		credentials, edgexErr := d.tryGetCredentialsInternal(secretName)
+		if edgexErr != nil {
+			d.lc.Debugf("Skipping the credential group %s for the camera %s: %s", secretName, endpointRef, edgexErr.Error())
+			continue
+		}
+		if !d.credentialTrials.reserve(endpointRef, maxAttempts, window, time.Now()) {
+			d.lc.Infof("The camera %s has no credential attempts left, skipping the remaining credential groups", endpointRef)
+			return nil, nil, "", false
+		}
+
+		onvifDevice, err := d.newOnvifDevice(xAddr, device.Protocols, credentials, tlsSettings)
+		if err != nil {
+			d.lc.Debugf("Failed to connect to the camera %s using the credential group %s: %s", endpointRef, secretName, err.Error())
+			continue
+		}
+		onvifClient := &OnvifClient{
+			driver:      d,
+			lc:          d.lc,
+			DeviceName:  device.Name,
+			onvifDevice: onvifDevice,
+			serviceURL:  deviceServiceURL(xAddr, device.Protocols),
+			tlsSettings: tlsSettings,
+		}
+		devInfo, edgexErr := onvifClient.getDeviceInformation(device)
+		if edgexErr == nil {
+			d.lc.Infof("The camera %s authenticated using the credential group %s", endpointRef, secretName)
+			d.credentialTrials.reset(endpointRef)
+			return onvifClient, devInfo, secretName, true
+		}
+		if isLockoutError(edgexErr) {
+			d.lc.Warnf("The camera %s reported a lockout, skipping the remaining credential groups: %s", endpointRef, edgexErr.Error())
+			d.credentialTrials.exhaust(endpointRef, maxAttempts)
+			return nil, nil, "", false
+		}
+		d.lc.Debugf("The camera %s failed to authenticate using the credential group %s: %s", endpointRef, secretName, edgexErr.Error())
+	}
+	return nil, nil, "", false
+}
+
+// learnCredentialGroup records the credential group stored in the protocol properties of a device against its MAC address
+func (d *Driver) learnCredentialGroup(protocols map[string]models.ProtocolProperties) {
+	secretName := cast.ToString(protocols[OnvifProtocol][CredentialGroup])
+	mac := cast.ToString(protocols[OnvifProtocol][MACAddress])
+	if secretName == "" || mac == "" {
+		return
+	}
+	if d.macAddressMapper.LearnMapping(mac, secretName) {
+		d.lc.Debugf("MAC address %s is using the credential group %s found by a credential trial", mac, secretName)
+	}
+}
+
+// credentialTrialEnabled returns true if discovery should try the other credential groups against cameras which fail to authenticate
+func (d *Driver) credentialTrialEnabled() bool {
+	d.configMu.RLock()
+	defer d.configMu.RUnlock()
+	return d.config.AppCustom.EnableCredentialTrial && d.config.AppCustom.CredentialTrialMaxAttempts > 0
+}
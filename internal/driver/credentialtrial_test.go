@@ -0,0 +1,192 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IOTechSystems/onvif"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialTrialTracker(t *testing.T) {
+	tracker := credentialTrialTracker{}
+	start := time.Now()
+	window := 15 * time.Minute
+
+	assert.True(t, tracker.reserve("camera1", 2, window, start))
+	assert.True(t, tracker.reserve("camera1", 2, window, start.Add(time.Minute)))
+	assert.False(t, tracker.reserve("camera1", 2, window, start.Add(2*time.Minute)))
+	// other cameras have their own budget
+	assert.True(t, tracker.reserve("camera2", 2, window, start))
+	// the budget is reset once the window has elapsed
+	assert.True(t, tracker.reserve("camera1", 2, window, start.Add(window)))
+
+	// a lockout uses up the remaining attempts
+	tracker.exhaust("camera2", 2)
+	assert.False(t, tracker.reserve("camera2", 2, window, start.Add(time.Minute)))
+
+	tracker.reset("camera2")
+	assert.True(t, tracker.reserve("camera2", 2, window, start.Add(time.Minute)))
+}
+
+func TestCredentialTrialSecretNames(t *testing.T) {
+	tests := []struct {
+		name              string
+		credentialsMap    map[string]string
+		defaultSecretName string
+		failedSecretName  string
+		expected          []string
+	}{
+		{
+			name:              "default secret name failed",
+			credentialsMap:    map[string]string{"NoAuth": "", "credentials002": "", "credentials001": "aa:bb:cc:dd:ee:ff"},
+			defaultSecretName: "credentials003",
+			failedSecretName:  "credentials003",
+			expected:          []string{"credentials001", "credentials002"},
+		},
+		{
+			name:              "mapped secret name failed",
+			credentialsMap:    map[string]string{"credentials001": "aa:bb:cc:dd:ee:ff", "credentials002": ""},
+			defaultSecretName: "credentials001",
+			failedSecretName:  "credentials002",
+			expected:          []string{"credentials001"},
+		},
+		{
+			name:             "nothing else to try",
+			credentialsMap:   map[string]string{"noauth": ""},
+			failedSecretName: "credentials001",
+			expected:         []string{},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			actual := credentialTrialSecretNames(test.credentialsMap, test.defaultSecretName, test.failedSecretName)
+			assert.ElementsMatch(t, test.expected, actual)
+			assert.IsIncreasing(t, actual)
+		})
+	}
+}
+
+func TestIsAuthAndLockoutError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     errors.EdgeX
+		auth    bool
+		lockout bool
+	}{
+		{
+			name: "unauthorized status",
+			err:  errors.NewCommonEdgeXWrapper(errors.NewCommonEdgeX(errors.KindInvalidId, "unauthorized", nil)),
+			auth: true,
+		},
+		{
+			name: "soap fault",
+			err:  errors.NewCommonEdgeX(errors.KindServerError, "ter:NotAuthorized: Sender not Authorized", nil),
+			auth: true,
+		},
+		{
+			name:    "locked status",
+			err:     errors.NewCommonEdgeXWrapper(errors.NewCommonEdgeX(errors.KindServiceLocked, "too many requests", nil)),
+			lockout: true,
+		},
+		{
+			name:    "locked fault",
+			err:     errors.NewCommonEdgeX(errors.KindServerError, "the user account is locked", nil),
+			lockout: true,
+		},
+		{
+			name:    "locked out fault",
+			err:     errors.NewCommonEdgeX(errors.KindInvalidId, "fault reason: user locked out", nil),
+			auth:    true,
+			lockout: true,
+		},
+		{
+			name: "other error",
+			err:  errors.NewCommonEdgeX(errors.KindServerError, "connection refused", nil),
+		},
+		{
+			name: "clock",
+			err:  errors.NewCommonEdgeX(errors.KindServerError, "the clock of the camera is not synchronized", nil),
+		},
+		{
+			name: "unlocked account",
+			err:  errors.NewCommonEdgeX(errors.KindServerError, "the user account is unlocked", nil),
+		},
+		{
+			name: "locked configuration",
+			err:  errors.NewCommonEdgeX(errors.KindContractInvalid, "the configuration is locked by another client", nil),
+		},
+		{
+			name: "authorization wording",
+			err:  errors.NewCommonEdgeX(errors.KindServerError, "the action is not authorized while recording", nil),
+		},
+		{
+			name: "other fault subcode",
+			err:  errors.NewCommonEdgeX(errors.KindContractInvalid, "fault code: env:Sender ter:InvalidArgVal", nil),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.auth, isAuthError(test.err))
+			assert.Equal(t, test.lockout, isLockoutError(test.err))
+		})
+	}
+}
+
+func TestIsNotAuthorizedFault(t *testing.T) {
+	tests := []struct {
+		name     string
+		fault    string
+		expected bool
+	}{
+		{
+			name:     "no fault",
+			expected: false,
+		},
+		{
+			name:     "not authorized subcode",
+			fault:    `<s:Fault><s:Code><s:Value>s:Sender</s:Value><s:Subcode><s:Value>ter:NotAuthorized</s:Value></s:Subcode></s:Code></s:Fault>`,
+			expected: true,
+		},
+		{
+			name: "nested not authorized subcode",
+			fault: `<s:Fault><s:Code><s:Value>s:Sender</s:Value><s:Subcode><s:Value>ter:OperationProhibited</s:Value>` +
+				`<s:Subcode><s:Value>ter:NotAuthorized</s:Value></s:Subcode></s:Subcode></s:Code></s:Fault>`,
+			expected: true,
+		},
+		{
+			name:     "other subcode",
+			fault:    `<s:Fault><s:Code><s:Value>s:Sender</s:Value><s:Subcode><s:Value>ter:InvalidArgVal</s:Value></s:Subcode></s:Code></s:Fault>`,
+			expected: false,
+		},
+		{
+			name:     "reason only",
+			fault:    `<s:Fault><s:Reason><s:Text>Sender not Authorized</s:Text></s:Reason></s:Fault>`,
+			expected: false,
+		},
+	}
+
+	function, edgexErr := onvif.FunctionByServiceAndFunctionName(onvif.DeviceWebService, onvif.GetDeviceInformation)
+	require.NoError(t, edgexErr)
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			envelope, edgexErr := createResponse(function, []byte(fmt.Sprintf(soapEnvelopeFormat, test.fault)))
+			require.NoError(t, edgexErr)
+			assert.Equal(t, test.expected, isNotAuthorizedFault(envelope.Body.Fault))
+		})
+	}
+}
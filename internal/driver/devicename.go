@@ -0,0 +1,200 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+
+	onvifdevice "github.com/IOTechSystems/onvif/device"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/spf13/cast"
+)
+
+// onvifScopePrefix is the prefix of the standard onvif scopes advertised by a camera
+const onvifScopePrefix = "onvif://www.onvif.org/"
+
+// DeviceNameTemplate is a text/template used to build the name of discovered cameras, for example
+// "{{.Location}}-{{.Model}}-{{.SerialNumber}}". The available fields are those of DeviceNameFields.
+// An empty template builds the default <Manufacturer>-<Model>-<EndpointRefAddress> names.
+type DeviceNameTemplate string
+
+// DeviceNameFields holds the values which can be used in a DeviceNameTemplate. Each value has the characters
+// which are not allowed in a device name replaced with a dash.
+type DeviceNameFields struct {
+	Manufacturer       string
+	Model              string
+	SerialNumber       string
+	FirmwareVersion    string
+	HardwareId         string
+	EndpointRefAddress string
+	MACAddress         string
+	Address            string
+	// Name is the value of the onvif name scope
+	Name string
+	// Location is the value of the first onvif location scope
+	Location string
+	// Hardware is the value of the onvif hardware scope
+	Hardware string
+}
+
+// Validate returns an error if the template cannot be parsed
+func (nameTemplate DeviceNameTemplate) Validate() error {
+	_, err := nameTemplate.parse()
+	return err
+}
+
+// Build executes the template with the fields, and returns the resulting device name
+func (nameTemplate DeviceNameTemplate) Build(fields DeviceNameFields) (string, error) {
+	if strings.TrimSpace(string(nameTemplate)) == "" {
+		return buildDeviceName(fields.Manufacturer, fields.Model, fields.EndpointRefAddress), nil
+	}
+
+	tmpl, err := nameTemplate.parse()
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err = tmpl.Execute(&sb, fields.sanitized()); err != nil {
+		return "", fmt.Errorf("failed to execute device name template %q: %w", nameTemplate, err)
+	}
+	name := sanitizeDeviceNamePart(sb.String())
+	if name == "" {
+		return "", fmt.Errorf("device name template %q resulted in an empty device name", nameTemplate)
+	}
+	return name, nil
+}
+
+func (nameTemplate DeviceNameTemplate) parse() (*template.Template, error) {
+	tmpl, err := template.New("DeviceNameTemplate").Option("missingkey=error").Parse(string(nameTemplate))
+	if err != nil {
+		return nil, fmt.Errorf("invalid device name template %q: %w", nameTemplate, err)
+	}
+	return tmpl, nil
+}
+
+// sanitized returns a copy of the fields with every value sanitized for use in a device name
+func (fields DeviceNameFields) sanitized() DeviceNameFields {
+	return DeviceNameFields{
+		Manufacturer:       sanitizeDeviceNamePart(fields.Manufacturer),
+		Model:              sanitizeDeviceNamePart(fields.Model),
+		SerialNumber:       sanitizeDeviceNamePart(fields.SerialNumber),
+		FirmwareVersion:    sanitizeDeviceNamePart(fields.FirmwareVersion),
+		HardwareId:         sanitizeDeviceNamePart(fields.HardwareId),
+		EndpointRefAddress: sanitizeDeviceNamePart(fields.EndpointRefAddress),
+		MACAddress:         sanitizeDeviceNamePart(fields.MACAddress),
+		Address:            sanitizeDeviceNamePart(fields.Address),
+		Name:               sanitizeDeviceNamePart(fields.Name),
+		Location:           sanitizeDeviceNamePart(fields.Location),
+		Hardware:           sanitizeDeviceNamePart(fields.Hardware),
+	}
+}
+
+// newDeviceNameFields creates the DeviceNameFields from the onvif protocol properties of a device. If devInfo
+// is not nil, it takes precedence over the device information stored in the protocol properties.
+func newDeviceNameFields(protocols map[string]models.ProtocolProperties, devInfo *onvifdevice.GetDeviceInformationResponse) DeviceNameFields {
+	onvifInfo := protocols[OnvifProtocol]
+	scopes := scopeValues(cast.ToString(onvifInfo[Scopes]))
+	fields := DeviceNameFields{
+		Manufacturer:       cast.ToString(onvifInfo[Manufacturer]),
+		Model:              cast.ToString(onvifInfo[Model]),
+		SerialNumber:       cast.ToString(onvifInfo[SerialNumber]),
+		FirmwareVersion:    cast.ToString(onvifInfo[FirmwareVersion]),
+		HardwareId:         cast.ToString(onvifInfo[HardwareId]),
+		EndpointRefAddress: cast.ToString(onvifInfo[EndpointRefAddress]),
+		MACAddress:         cast.ToString(onvifInfo[MACAddress]),
+		Address:            cast.ToString(onvifInfo[Address]),
+		Name:               scopes["name"],
+		Location:           scopes["location"],
+		Hardware:           scopes["hardware"],
+	}
+	if devInfo != nil {
+		fields.Manufacturer = devInfo.Manufacturer
+		fields.Model = devInfo.Model
+		fields.SerialNumber = devInfo.SerialNumber
+		fields.FirmwareVersion = devInfo.FirmwareVersion
+		fields.HardwareId = devInfo.HardwareId
+	}
+	return fields
+}
+
+// scopeValues parses the space separated onvif scopes of a camera into a map of the scope category to its unescaped
+// value, for example onvif://www.onvif.org/location/building/office becomes location: building/office. Only the first
+// scope of each category is kept.
+func scopeValues(scopes string) map[string]string {
+	values := make(map[string]string)
+	for _, scope := range strings.Fields(scopes) {
+		categoryAndValue, found := strings.CutPrefix(scope, onvifScopePrefix)
+		if !found {
+			continue
+		}
+		category, value, found := strings.Cut(categoryAndValue, "/")
+		if !found || value == "" {
+			continue
+		}
+		if _, exists := values[category]; exists {
+			continue
+		}
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			values[category] = unescaped
+		}
+	}
+	return values
+}
+
+// makeUniqueDeviceName returns the name if it is not in the used set, otherwise the name suffixed with the
+// EndpointRefAddress of the camera, followed by a counter if still not unique. The returned name is added to the set.
+func makeUniqueDeviceName(name string, endpointRefAddress string, used map[string]struct{}) string {
+	unique := name
+	if _, found := used[unique]; found {
+		if suffix := sanitizeDeviceNamePart(endpointRefAddress); suffix != "" && !strings.HasSuffix(name, suffix) {
+			unique = name + "-" + suffix
+		}
+	}
+	base := unique
+	for i := 2; ; i++ {
+		if _, found := used[unique]; !found {
+			break
+		}
+		unique = fmt.Sprintf("%s-%d", base, i)
+	}
+	used[unique] = struct{}{}
+	return unique
+}
+
+// buildDeviceNameForDevice builds the name of a device using the configured DeviceNameTemplate, falling back
+// to the default name if the template fails.
+func (d *Driver) buildDeviceNameForDevice(protocols map[string]models.ProtocolProperties, devInfo *onvifdevice.GetDeviceInformationResponse) string {
+	d.configMu.RLock()
+	nameTemplate := d.config.AppCustom.DeviceNameTemplate
+	d.configMu.RUnlock()
+
+	fields := newDeviceNameFields(protocols, devInfo)
+	name, err := nameTemplate.Build(fields)
+	if err != nil {
+		d.lc.Warnf("Unable to build the device name using the DeviceNameTemplate, falling back to the default name: %s", err.Error())
+		return buildDeviceName(fields.Manufacturer, fields.Model, fields.EndpointRefAddress)
+	}
+	return name
+}
+
+// mergeLabels returns the labels with any of the added labels which are not empty and not already present appended
+func mergeLabels(labels []string, added ...string) []string {
+	merged := append([]string{}, labels...)
+	for _, label := range added {
+		if label == "" || slices.Contains(merged, label) {
+			continue
+		}
+		merged = append(merged, label)
+	}
+	return merged
+}
@@ -0,0 +1,94 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceNameTemplate_Build(t *testing.T) {
+	fields := newDeviceNameFields(map[string]models.ProtocolProperties{
+		OnvifProtocol: {
+			Manufacturer:       "Tapo (TP-Link)",
+			Model:              "C200/WS",
+			SerialNumber:       "SN 1234",
+			EndpointRefAddress: "abcdef-ghij",
+			Scopes: "onvif://www.onvif.org/type/video_encoder onvif://www.onvif.org/location/building/Main%20Office " +
+				"onvif://www.onvif.org/location/floor/2 onvif://www.onvif.org/name/Front%20Door",
+		},
+	}, nil)
+
+	tests := []struct {
+		name          string
+		template      DeviceNameTemplate
+		expected      string
+		errorExpected bool
+	}{
+		{
+			name:     "default",
+			template: "",
+			expected: "Tapo-TP-Link-C200-WS-abcdef-ghij",
+		},
+		{
+			name:     "location model serial",
+			template: "{{.Location}}-{{.Model}}-{{.SerialNumber}}",
+			expected: "building-Main-Office-C200-WS-SN-1234",
+		},
+		{
+			name:     "literal text sanitized",
+			template: "cam {{.Name}}",
+			expected: "cam-Front-Door",
+		},
+		{
+			name:     "empty field trimmed",
+			template: "{{.Hardware}}-{{.EndpointRefAddress}}",
+			expected: "abcdef-ghij",
+		},
+		{
+			name:          "unknown field",
+			template:      "{{.Unknown}}",
+			errorExpected: true,
+		},
+		{
+			name:          "invalid template",
+			template:      "{{.Model",
+			errorExpected: true,
+		},
+		{
+			name:          "empty result",
+			template:      "{{.Hardware}}",
+			errorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			name, err := test.template.Build(fields)
+			if test.errorExpected {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, name)
+		})
+	}
+}
+
+func TestMakeUniqueDeviceName(t *testing.T) {
+	used := map[string]struct{}{"Lobby-Camera": {}}
+
+	assert.Equal(t, "Office-Camera", makeUniqueDeviceName("Office-Camera", "abcd", used))
+	assert.Equal(t, "Lobby-Camera-abcd", makeUniqueDeviceName("Lobby-Camera", "abcd", used))
+	assert.Equal(t, "Lobby-Camera-abcd-2", makeUniqueDeviceName("Lobby-Camera", "abcd", used))
+	assert.Equal(t, "Office-Camera-2", makeUniqueDeviceName("Office-Camera", "", used))
+	assert.Len(t, used, 5)
+}
@@ -0,0 +1,53 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/edgexfoundry/device-sdk-go/v4/pkg/interfaces"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	DiscoveryStatusRestPath = "discoverystatus"
+	apiDiscoveryStatusRoute = common.ApiBase + "/" + DiscoveryStatusRestPath
+	apiDiscoveryCancelRoute = apiDiscoveryStatusRoute + "/cancel"
+)
+
+// addDiscoveryRoutes adds the routes for querying the progress of discovery, and cancelling it
+func (d *Driver) addDiscoveryRoutes() errors.EdgeX {
+	if err := d.sdkService.AddCustomRoute(apiDiscoveryStatusRoute, interfaces.Authenticated, d.getDiscoveryStatus, http.MethodGet); err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("unable to add required route: %s: %s", apiDiscoveryStatusRoute, err.Error()), err)
+	}
+	d.lc.Infof("Route %s added.", apiDiscoveryStatusRoute)
+
+	if err := d.sdkService.AddCustomRoute(apiDiscoveryCancelRoute, interfaces.Authenticated, d.cancelDiscovery, http.MethodPost); err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("unable to add required route: %s: %s", apiDiscoveryCancelRoute, err.Error()), err)
+	}
+	d.lc.Infof("Route %s added.", apiDiscoveryCancelRoute)
+	return nil
+}
+
+// getDiscoveryStatus returns the status and progress of the current, or most recent, discovery
+func (d *Driver) getDiscoveryStatus(c echo.Context) error {
+	return c.JSON(http.StatusOK, d.discoveryTracker.getStatus())
+}
+
+// cancelDiscovery cancels the running discovery. Any devices discovered up until this point are
+// still passed on to the provision watchers.
+func (d *Driver) cancelDiscovery(c echo.Context) error {
+	if !d.discoveryTracker.cancelRunning() {
+		return c.String(http.StatusConflict, "discovery is not running")
+	}
+	d.lc.Info("Discovery cancelled via the REST API.")
+	return c.JSON(http.StatusAccepted, d.discoveryTracker.getStatus())
+}
@@ -0,0 +1,54 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_CancelDiscovery(t *testing.T) {
+	driver, _ := createDriverWithMockService()
+	e := echo.New()
+
+	// cancelling is refused when no discovery is running
+	rec := httptest.NewRecorder()
+	require.NoError(t, driver.cancelDiscovery(e.NewContext(httptest.NewRequest(http.MethodPost, apiDiscoveryCancelRoute, nil), rec)))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	ctx, progress := driver.discoveryTracker.start(time.Minute)
+	require.NotNil(t, progress)
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, driver.getDiscoveryStatus(e.NewContext(httptest.NewRequest(http.MethodGet, apiDiscoveryStatusRoute, nil), rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"Running":true`)
+	assert.Contains(t, rec.Body.String(), `"EstimatedProbes":`)
+	var status DiscoveryStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.True(t, status.Running)
+	assert.False(t, status.Cancelled)
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, driver.cancelDiscovery(e.NewContext(httptest.NewRequest(http.MethodPost, apiDiscoveryCancelRoute, nil), rec)))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	driver.discoveryTracker.finish()
+	status = driver.discoveryTracker.getStatus()
+	assert.False(t, status.Running)
+	assert.True(t, status.Cancelled)
+	assert.False(t, status.FinishedAt.Before(status.StartedAt))
+}
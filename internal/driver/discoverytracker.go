@@ -0,0 +1,93 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-onvif-camera/internal/netscan"
+)
+
+// DiscoveryStatus describes the state of the current, or most recent, discovery
+type DiscoveryStatus struct {
+	// Running indicates if a discovery is in progress
+	Running bool
+	// Cancelled indicates if the discovery was cancelled via the cancel route
+	Cancelled bool
+	// StartedAt is the time the discovery started, or the zero time if discovery has never run
+	StartedAt time.Time
+	// FinishedAt is the time the discovery finished, or the zero time if it is still running
+	FinishedAt time.Time
+	// Netscan holds the progress of the netscan discovery
+	Netscan netscan.ProgressSnapshot
+}
+
+// discoveryTracker keeps track of the progress of the running discovery, and allows it to be cancelled
+type discoveryTracker struct {
+	mu       sync.Mutex
+	status   DiscoveryStatus
+	progress *netscan.Progress
+	cancel   context.CancelFunc
+}
+
+// start marks the beginning of a new discovery, and returns the context the discovery must run with
+// along with the progress to publish to. If maxDuration is greater than zero, the context times out
+// after it. The returned context is cancelled when either cancel or finish are called.
+func (t *discoveryTracker) start(maxDuration time.Duration) (context.Context, *netscan.Progress) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if maxDuration > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), maxDuration)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progress = &netscan.Progress{}
+	t.cancel = cancel
+	t.status = DiscoveryStatus{
+		Running:   true,
+		StartedAt: time.Now(),
+	}
+	return ctx, t.progress
+}
+
+// finish marks the end of the running discovery and releases its context
+func (t *discoveryTracker) finish() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cancel != nil {
+		t.cancel()
+		t.cancel = nil
+	}
+	t.status.Running = false
+	t.status.FinishedAt = time.Now()
+}
+
+// cancelRunning cancels the context of the running discovery. Returns false if no discovery is running.
+func (t *discoveryTracker) cancelRunning() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.status.Running || t.cancel == nil {
+		return false
+	}
+	t.cancel()
+	t.status.Cancelled = true
+	return true
+}
+
+// getStatus returns the status of the current, or most recent, discovery
+func (t *discoveryTracker) getStatus() DiscoveryStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	status := t.status
+	status.Netscan = t.progress.Snapshot()
+	return status
+}
@@ -0,0 +1,64 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// windowTimeLayout is the layout of the start and end time of a DiscoveryWindow
+const windowTimeLayout = "15:04"
+
+// DiscoveryWindow is a time of day range in the format HH:MM-HH:MM, in the local time of the service.
+// A window whose end is before its start spans midnight. An empty window allows discovery at any time.
+type DiscoveryWindow string
+
+// Contains returns true if the time of day of t is within the window. The start of the window is
+// inclusive, and the end is exclusive.
+func (window DiscoveryWindow) Contains(t time.Time) (bool, error) {
+	if strings.TrimSpace(string(window)) == "" {
+		return true, nil
+	}
+
+	start, end, err := window.parse()
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if start <= end {
+		return now >= start && now < end, nil
+	}
+	// the window spans midnight
+	return now >= start || now < end, nil
+}
+
+// parse returns the start and end of the window as an offset from midnight
+func (window DiscoveryWindow) parse() (start time.Duration, end time.Duration, err error) {
+	startStr, endStr, found := strings.Cut(string(window), "-")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid discovery window %q, expected the format HH:MM-HH:MM", window)
+	}
+	if start, err = parseTimeOfDay(startStr); err != nil {
+		return 0, 0, fmt.Errorf("invalid start of discovery window %q: %w", window, err)
+	}
+	if end, err = parseTimeOfDay(endStr); err != nil {
+		return 0, 0, fmt.Errorf("invalid end of discovery window %q: %w", window, err)
+	}
+	return start, end, nil
+}
+
+// parseTimeOfDay parses a HH:MM formatted time into an offset from midnight
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse(windowTimeLayout, strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,56 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiscoveryWindowContains verifies the time of day checks of the discovery window setting.
+func TestDiscoveryWindowContains(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 1, 2, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name          string
+		window        DiscoveryWindow
+		time          time.Time
+		expected      bool
+		errorExpected bool
+	}{
+		{name: "empty window", window: "", time: at(12, 0), expected: true},
+		{name: "inside window", window: "09:00-17:00", time: at(12, 0), expected: true},
+		{name: "start is inclusive", window: "09:00-17:00", time: at(9, 0), expected: true},
+		{name: "end is exclusive", window: "09:00-17:00", time: at(17, 0), expected: false},
+		{name: "outside window", window: "09:00-17:00", time: at(20, 30), expected: false},
+		{name: "spans midnight before midnight", window: "22:00-06:00", time: at(23, 15), expected: true},
+		{name: "spans midnight after midnight", window: "22:00-06:00", time: at(2, 0), expected: true},
+		{name: "outside window spanning midnight", window: "22:00-06:00", time: at(12, 0), expected: false},
+		{name: "surrounding spaces", window: " 22:00 - 06:00 ", time: at(2, 0), expected: true},
+		{name: "missing end", window: "22:00", time: at(2, 0), errorExpected: true},
+		{name: "invalid start", window: "25:00-06:00", time: at(2, 0), errorExpected: true},
+		{name: "invalid end", window: "22:00-6pm", time: at(2, 0), errorExpected: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			contains, err := test.window.Contains(test.time)
+			if test.errorExpected {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, contains)
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -60,16 +61,48 @@ type Driver struct {
 	debounceTimer *time.Timer
 	debounceMu    sync.Mutex
 
+	// discoveryTracker keeps track of the progress of discovery, and allows it to be cancelled
+	discoveryTracker discoveryTracker
+
+	// relocationCache holds the multicast probe results used to relocate Unreachable devices
+	relocationCache relocationProbeCache
+
+	// credentialTrials keeps track of the credential groups tried against discovered cameras
+	credentialTrials credentialTrialTracker
+
+	// staleDevices keeps track of the status checks and discoveries which missed each device
+	staleDevices staleDeviceTracker
+
+	// statusSchedule keeps track of when the status of each device was last checked
+	statusSchedule statusSchedule
+	// statusCheckMetrics keeps track of the duration and skipped devices of the status check rounds
+	statusCheckMetrics statusCheckMetrics
+	// health keeps track of the health record of each device, and applies the status hysteresis thresholds
+	health deviceHealthTracker
+	// pendingLastSeen keeps track of the devices whose LastSeen is persisted at the end of the status check round
+	pendingLastSeen pendingLastSeen
+	// credentialRotationMu prevents credential rotations from running simultaneously
+	credentialRotationMu sync.Mutex
+	// secretIndex keeps track of the devices which depend on each secret name
+	secretIndex secretIndex
+	// stopping is set once Stop is called, after which no status change reading is sent to the async values channel.
+	// stoppingMu is held for reading while a reading is sent, so that the channel is not closed in the meantime.
+	stopping   bool
+	stoppingMu sync.RWMutex
+
 	// taskCh is used to send signals to the taskLoop
 	taskCh chan struct{}
-	wg     sync.WaitGroup
+	// configChangedCh is used to wake up the taskLoop when the writable configuration changes
+	configChangedCh chan struct{}
+	wg              sync.WaitGroup
 }
 
 func NewDriver() *Driver {
 	return &Driver{
-		onvifClients: make(map[string]*OnvifClient),
-		config:       &ServiceConfig{},
-		taskCh:       make(chan struct{}),
+		onvifClients:    make(map[string]*OnvifClient),
+		config:          &ServiceConfig{},
+		taskCh:          make(chan struct{}),
+		configChangedCh: make(chan struct{}, 1),
 	}
 }
 
@@ -92,6 +125,10 @@ func (d *Driver) Initialize(sdk interfaces.DeviceServiceSDK) error {
 			d.config.AppCustom.DiscoveryMode)
 	}
 
+	if err = d.config.AppCustom.DeviceNameTemplate.Validate(); err != nil {
+		d.lc.Errorf("DeviceNameTemplate is set to an invalid value, the default device names will be used: %s", err.Error())
+	}
+
 	err = d.sdkService.SecretProvider().RegisterSecretUpdatedCallback(secret.WildcardName, d.secretUpdated)
 	if err != nil {
 		d.lc.Errorf("failed to register secret update callback: %v", err)
@@ -110,6 +147,31 @@ func (d *Driver) Initialize(sdk interfaces.DeviceServiceSDK) error {
 		return errors.NewCommonEdgeXWrapper(edgexErr)
 	}
 
+	edgexErr = d.addDiscoveryRoutes()
+	if edgexErr != nil {
+		return errors.NewCommonEdgeXWrapper(edgexErr)
+	}
+
+	edgexErr = d.addDuplicateRoutes()
+	if edgexErr != nil {
+		return errors.NewCommonEdgeXWrapper(edgexErr)
+	}
+
+	edgexErr = d.addStatusCheckRoutes()
+	if edgexErr != nil {
+		return errors.NewCommonEdgeXWrapper(edgexErr)
+	}
+
+	edgexErr = d.addHealthRoutes()
+	if edgexErr != nil {
+		return errors.NewCommonEdgeXWrapper(edgexErr)
+	}
+
+	edgexErr = d.addCredentialRoutes()
+	if edgexErr != nil {
+		return errors.NewCommonEdgeXWrapper(edgexErr)
+	}
+
 	d.lc.Info("Driver initialized.")
 	return nil
 }
@@ -117,40 +179,72 @@ func (d *Driver) Initialize(sdk interfaces.DeviceServiceSDK) error {
 // Start is called after the device sdk is fully initialized. This function creates connections to all the cameras
 // and checks their statuses. It then runs the task loop if enabled.
 func (d *Driver) Start() error {
-	wg := sync.WaitGroup{}
-	for _, device := range d.sdkService.Devices() {
-		device := device
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
+	devices := d.sdkService.Devices()
+	// restore the credential groups found by credential trials before any clients are created
+	for _, device := range devices {
+		d.learnCredentialGroup(device.Protocols)
+	}
+	d.indexDeviceSecrets(devices...)
 
-			d.lc.Infof("Initializing onvif client for '%s' camera", device.Name)
-			_, err := d.getOrCreateOnvifClient(device)
-			if err != nil {
-				d.lc.Errorf("failed to initialize onvif client for '%s' camera, skipping this device.", device.Name)
-				return
-			}
-			d.checkStatusOfDevice(device)
-		}()
+	// the first status check round uses the same bounded pool of workers, jitter and deadline as the task loop, so
+	// that starting with many cameras does not check all of them at once
+	if initialized := d.initializeOnvifClients(devices); len(initialized) > 0 {
+		d.checkStatusOfDevices(initialized, d.statusCheckRoundTimeout())
 	}
-	wg.Wait()
 
+	// starts loop to check connection and determine device status. The loop always runs, so that status checking
+	// can be enabled via the writable configuration.
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done() // wait for taskLoop to return
+		d.taskLoop()
+		d.lc.Info("taskLoop has stopped.")
+	}()
+
+	d.lc.Info("Driver started.")
+	return nil
+}
+
+// initializeOnvifClients creates the onvif clients of the devices, using at most StatusCheckWorkers simultaneous
+// workers, and returns the devices whose client was created
+func (d *Driver) initializeOnvifClients(devices []models.Device) []models.Device {
 	d.configMu.RLock()
-	enableStatusCheck := d.config.AppCustom.EnableStatusCheck
+	workers := d.config.AppCustom.StatusCheckWorkers
 	d.configMu.RUnlock()
+	if workers <= 0 || workers > len(devices) {
+		workers = len(devices)
+	}
 
-	if enableStatusCheck {
-		// starts loop to check connection and determine device status
-		d.wg.Add(1)
+	initialized := make([]bool, len(devices))
+	queue := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
 		go func() {
-			defer d.wg.Done() // wait for taskLoop to return
-			d.taskLoop()
-			d.lc.Info("taskLoop has stopped.")
+			defer wg.Done()
+			for i := range queue {
+				d.lc.Infof("Initializing onvif client for '%s' camera", devices[i].Name)
+				if _, err := d.getOrCreateOnvifClient(devices[i]); err != nil {
+					d.lc.Errorf("failed to initialize onvif client for '%s' camera, skipping this device.", devices[i].Name)
+					continue
+				}
+				initialized[i] = true
+			}
 		}()
 	}
+	for i := range devices {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
 
-	d.lc.Info("Driver started.")
-	return nil
+	result := make([]models.Device, 0, len(devices))
+	for i, device := range devices {
+		if initialized[i] {
+			result = append(result, device)
+		}
+	}
+	return result
 }
 
 // Stop the protocol-specific DS code to shutdown gracefully, or
@@ -162,9 +256,9 @@ func (d *Driver) Stop(force bool) error {
 	if d.sdkService == nil {
 		return nil
 	}
-	if d.sdkService.AsyncValuesChannel() != nil {
-		close(d.sdkService.AsyncValuesChannel())
-	}
+	d.stoppingMu.Lock()
+	d.stopping = true
+	d.stoppingMu.Unlock()
 
 	d.clientsMu.Lock()
 	for _, client := range d.onvifClients {
@@ -177,12 +271,19 @@ func (d *Driver) Stop(force bool) error {
 	close(d.taskCh) // send signal for taskLoop to finish
 	d.wg.Wait()     // wait for taskLoop goroutine to return
 
+	// the status checks have stopped, so nothing sends to the async values channel anymore
+	if d.sdkService.AsyncValuesChannel() != nil {
+		close(d.sdkService.AsyncValuesChannel())
+	}
+
 	return nil
 }
 
 // AddDevice is a callback function that is invoked
 // when a new Device associated with this Device Service is added
 func (d *Driver) AddDevice(deviceName string, protocols map[string]models.ProtocolProperties, adminState models.AdminState) error {
+	d.learnCredentialGroup(protocols)
+	d.indexDeviceSecrets(models.Device{Name: deviceName, Protocols: protocols})
 	_, err := d.getOrCreateOnvifClient(models.Device{Name: deviceName, Protocols: protocols})
 	if err != nil {
 		d.lc.Errorf("Failed to initialize onvif client for camera '%s'", deviceName)
@@ -196,6 +297,7 @@ func (d *Driver) AddDevice(deviceName string, protocols map[string]models.Protoc
 // UpdateDevice is a callback function that is invoked
 // when a Device associated with this Device Service is updated
 func (d *Driver) UpdateDevice(deviceName string, protocols map[string]models.ProtocolProperties, adminState models.AdminState) error {
+	d.indexDeviceSecrets(models.Device{Name: deviceName, Protocols: protocols})
 	// Invoke the updateOnvifClient func to update the old onvif client if needed
 	err := d.updateOnvifClient(models.Device{Name: deviceName, Protocols: protocols})
 	if err != nil {
@@ -208,6 +310,10 @@ func (d *Driver) UpdateDevice(deviceName string, protocols map[string]models.Pro
 // when a Device associated with this Device Service is removed
 func (d *Driver) RemoveDevice(deviceName string, protocols map[string]models.ProtocolProperties) error {
 	d.removeOnvifClient(deviceName)
+	d.staleDevices.forget(deviceName)
+	d.statusSchedule.forget(deviceName)
+	d.health.forget(deviceName)
+	d.secretIndex.forget(deviceName)
 	return nil
 }
 
@@ -217,6 +323,9 @@ func (d *Driver) ValidateDevice(device models.Device) error {
 	if err != nil {
 		return fmt.Errorf("invalid protocol properties, %v", err)
 	}
+	if err = d.validateSecretName(device.Protocols); err != nil {
+		return fmt.Errorf("invalid protocol properties, %v", err)
+	}
 	return nil
 }
 
@@ -237,7 +346,7 @@ func (d *Driver) HandleReadCommands(deviceName string, protocols map[string]mode
 			return responses, errors.NewCommonEdgeXWrapper(edgexErr)
 		}
 
-		cv, edgexErr := onvifClient.CallOnvifFunction(req, GetFunction, data)
+		cv, edgexErr := d.callOnvifFunctionWithBackoff(deviceName, onvifClient, req, GetFunction, data)
 		if edgexErr != nil {
 			return responses, errors.NewCommonEdgeX(errors.KindServerError, "failed to execute read command", edgexErr)
 		}
@@ -282,7 +391,7 @@ func (d *Driver) HandleWriteCommands(deviceName string, protocols map[string]mod
 			return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("value type %s is not supported for write commands", req.Type), nil)
 		}
 
-		result, err := onvifClient.CallOnvifFunction(req, SetFunction, data)
+		result, err := d.callOnvifFunctionWithBackoff(deviceName, onvifClient, req, SetFunction, data)
 		if err != nil {
 			return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to execute write command, %s", result), err)
 		}
@@ -298,12 +407,27 @@ func (d *Driver) Discover() error {
 	d.configMu.RLock()
 	maxSeconds := d.config.AppCustom.MaxDiscoverDurationSeconds
 	discoveryMode := d.config.AppCustom.DiscoveryMode
+	discoveryWindow := d.config.AppCustom.DiscoveryWindow
+	skipUpWithAuth := d.config.AppCustom.DiscoverySkipUpWithAuth
 	d.configMu.RUnlock()
 
 	if !discoveryMode.IsValid() {
 		return fmt.Errorf("DiscoveryMode is set to an invalid value: %s. Refusing to do discovery", discoveryMode)
 	}
 
+	inWindow, err := discoveryWindow.Contains(time.Now())
+	if err != nil {
+		return fmt.Errorf("DiscoveryWindow is set to an invalid value: %s. Refusing to do discovery", err)
+	}
+	if !inWindow {
+		d.lc.Infof("Skipping discovery, as the current time is outside of the DiscoveryWindow %s", discoveryWindow)
+		return nil
+	}
+
+	started := time.Now()
+	ctx, progress := d.discoveryTracker.start(time.Duration(maxSeconds) * time.Second)
+	defer d.discoveryTracker.finish()
+
 	var discoveredDevices []sdkModel.DiscoveredDevice
 
 	if discoveryMode.IsMulticastEnabled() {
@@ -314,72 +438,147 @@ func (d *Driver) Discover() error {
 		discoveredDevices = append(discoveredDevices, devices...)
 	}
 
-	if discoveryMode.IsNetScanEnabled() {
-		ctx := context.Background()
-		if maxSeconds > 0 {
-			var cancel context.CancelFunc
-			ctx, cancel = context.WithTimeout(context.Background(),
-				time.Duration(maxSeconds)*time.Second)
-			defer cancel()
-		}
-		discoveredDevices = append(discoveredDevices, d.discoverNetscan(ctx)...)
+	if discoveryMode.IsNetScanEnabled() && ctx.Err() == nil {
+		discoveredDevices = append(discoveredDevices, d.discoverNetscan(ctx, progress)...)
 	}
 
+	// merge or report any devices registered more than once, so that the discovered devices are matched against a single device
+	d.reconcileDuplicateDevices()
+
 	// pass the discovered devices to the EdgeX SDK to be passed through to the provision watchers
 	filtered := d.discoverFilter(discoveredDevices)
 	d.sdkService.DiscoveredDeviceChannel() <- filtered
+
+	// only a discovery which ran to completion can tell that a device is missing
+	if ctx.Err() == nil {
+		d.recordMissedDiscoveries(started, skipUpWithAuth && discoveryMode.IsNetScanEnabled())
+	} else {
+		d.lc.Debugf("Discovery did not complete, devices which were not found are not counted as missed: %s", ctx.Err())
+	}
 	return nil
 }
 
 // multicast enable/disable via config option
 func (d *Driver) discoverMulticast() ([]sdkModel.DiscoveredDevice, errors.EdgeX) {
-	var discovered []sdkModel.DiscoveredDevice
-
 	d.configMu.RLock()
 	discoveryEthernetInterface := d.config.AppCustom.DiscoveryEthernetInterface
 	d.configMu.RUnlock()
 
+	ifaceNames, err := d.multicastInterfaceNames(discoveryEthernetInterface)
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.Kind(err), "failed to find the ethernet interfaces "+discoveryEthernetInterface, err)
+	}
+
+	var discovered []sdkModel.DiscoveredDevice
+	var errs MultiErr
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, ifaceName := range ifaceNames {
+		wg.Add(1)
+		go func(ifaceName string) {
+			defer wg.Done()
+			devices, err := d.discoverMulticastAtInterface(ifaceName)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				d.lc.Error(err.Error())
+				errs = append(errs, err)
+				return
+			}
+			discovered = append(discovered, devices...)
+		}(ifaceName)
+	}
+	wg.Wait()
+
+	// only fail when no interface could be probed at all
+	if len(errs) > 0 && len(errs) == len(ifaceNames) {
+		return nil, errors.NewCommonEdgeX(errors.KindServerError, "failed to discover devices from any of the ethernet interfaces", errs)
+	}
+	return discovered, nil
+}
+
+// discoverMulticastAtInterface sends a ws-discovery multicast probe on a single ethernet interface, and records the
+// interface in the protocol properties of each of the devices which responded.
+func (d *Driver) discoverMulticastAtInterface(ifaceName string) ([]sdkModel.DiscoveredDevice, errors.EdgeX) {
+	var discovered []sdkModel.DiscoveredDevice
+
 	t0 := time.Now()
-	onvifDevices, err := wsdiscovery.GetAvailableDevicesAtSpecificEthernetInterface(discoveryEthernetInterface)
+	probedDevices, err := d.sendMulticastProbe(ifaceName)
 	if err != nil {
-		return nil, errors.NewCommonEdgeX(errors.Kind(err), "failed to discover device from the ethernet interface "+discoveryEthernetInterface, err)
+		return nil, errors.NewCommonEdgeX(errors.Kind(err), "failed to discover device from the ethernet interface "+ifaceName, err)
 	}
-	d.lc.Infof("Discovered %d device(s) in %v via multicast.", len(onvifDevices), time.Since(t0))
-	for _, onvifDevice := range onvifDevices {
-		device, err := d.createDiscoveredDevice(onvifDevice)
+	d.lc.Infof("Discovered %d device(s) in %v via multicast on interface %s.", len(probedDevices), time.Since(t0), ifaceName)
+	for _, probed := range probedDevices {
+		device, err := d.createDiscoveredDevice(probed)
 		if err != nil {
 			d.lc.Warnf(err.Error())
 			continue
 		}
+		device.Protocols[OnvifProtocol][DiscoveryInterface] = ifaceName
 		discovered = append(discovered, device)
 	}
 
 	return discovered, nil
 }
 
+// sendMulticastProbe sends a ws-discovery multicast probe on a single ethernet interface, and returns the devices
+// which answered at one of their advertised XAddrs.
+func (d *Driver) sendMulticastProbe(ifaceName string) ([]probedDevice, error) {
+	d.configMu.RLock()
+	requestTimeout := time.Duration(d.config.AppCustom.RequestTimeout) * time.Second
+	d.configMu.RUnlock()
+
+	responses, err := wsdiscovery.SendProbe(ifaceName, nil, []string{"dn:NetworkVideoTransmitter"},
+		map[string]string{"dn": "http://www.onvif.org/ver10/network/wsdl", "ds": "http://www.onvif.org/ver10/device/wsdl"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe: %w", err)
+	}
+	return devicesFromProbeResponses(responses, requestTimeout, d.discoveryTLSConfig(), d.lc), nil
+}
+
 // netscan enable/disable via config option
-func (d *Driver) discoverNetscan(ctx context.Context) []sdkModel.DiscoveredDevice {
+func (d *Driver) discoverNetscan(ctx context.Context, progress *netscan.Progress) []sdkModel.DiscoveredDevice {
 	var discovered []sdkModel.DiscoveredDevice
 
-	if len(strings.TrimSpace(d.config.AppCustom.DiscoverySubnets)) == 0 {
-		d.lc.Warn("netscan discovery was called, but DiscoverySubnets are empty!")
+	d.configMu.RLock()
+	if len(strings.TrimSpace(d.config.AppCustom.DiscoverySubnets)) == 0 && len(d.config.AppCustom.DiscoveryTargets) == 0 {
+		d.configMu.RUnlock()
+		d.lc.Warnf("netscan discovery was called, but DiscoverySubnets and DiscoveryTargets are empty! Set DiscoverySubnets to %q to scan the local subnets.", AutoSubnets)
 		return nil
 	}
 
-	d.configMu.RLock()
 	params := netscan.Params{
 		// split the comma separated string here to avoid issues with EdgeX's Consul implementation
-		Subnets:         strings.Split(d.config.AppCustom.DiscoverySubnets, ","),
-		AsyncLimit:      d.config.AppCustom.ProbeAsyncLimit,
-		Timeout:         time.Duration(d.config.AppCustom.ProbeTimeoutMillis) * time.Millisecond,
-		ScanPorts:       []string{wsDiscoveryPort},
-		Logger:          d.lc,
-		NetworkProtocol: netscan.NetworkUDP,
-	}
+		Subnets:            strings.Split(d.config.AppCustom.DiscoverySubnets, ","),
+		Targets:            makeNetscanTargets(d.config.AppCustom.DiscoveryTargets),
+		Exclusions:         strings.Split(d.config.AppCustom.DiscoveryExclusions, ","),
+		AsyncLimit:         d.config.AppCustom.ProbeAsyncLimit,
+		Timeout:            time.Duration(d.config.AppCustom.ProbeTimeoutMillis) * time.Millisecond,
+		ScanPorts:          []string{wsDiscoveryPort},
+		Logger:             d.lc,
+		NetworkProtocol:    netscan.NetworkUDP,
+		MaxProbesPerSecond: d.config.AppCustom.MaxProbesPerSecond,
+		Jitter:             time.Duration(d.config.AppCustom.ProbeJitterMillis) * time.Millisecond,
+		RandomizeOrder:     d.config.AppCustom.RandomizeProbeOrder,
+		Progress:           progress,
+	}
+	enableHTTPDiscovery := d.config.AppCustom.EnableHTTPDiscovery
+	httpPorts := d.config.AppCustom.HTTPDiscoveryPorts
+	skipUpWithAuth := d.config.AppCustom.DiscoverySkipUpWithAuth
+	discoveryEthernetInterface := d.config.AppCustom.DiscoveryEthernetInterface
+	autoSubnetMaxSize := d.config.AppCustom.DiscoveryAutoSubnetMaxSize
 	d.configMu.RUnlock()
 
+	params.Subnets = d.expandAutoSubnets(params.Subnets, parseDiscoveryInterfaces(discoveryEthernetInterface), autoSubnetMaxSize)
+
+	skipHosts := make(map[string]struct{})
+	if skipUpWithAuth {
+		skipHosts = d.makeUpWithAuthHostSet()
+		d.lc.Debugf("netscan will skip %d registered device(s) which are %s", len(skipHosts), UpWithAuth)
+	}
+
 	t0 := time.Now()
-	result := netscan.AutoDiscover(ctx, NewOnvifProtocolDiscovery(d), params)
+	result := netscan.AutoDiscover(ctx, NewOnvifProtocolDiscovery(d, skipHosts), params)
 	if ctx.Err() != nil {
 		d.lc.Warnf("Discover process has been cancelled!", "ctxErr", ctx.Err())
 	}
@@ -388,9 +587,57 @@ func (d *Driver) discoverNetscan(ctx context.Context) []sdkModel.DiscoveredDevic
 	d.lc.Infof("Discovered %d device(s) in %v via netscan.", len(result), time.Since(t0))
 
 	discovered = append(discovered, result...)
+
+	if enableHTTPDiscovery && ctx.Err() == nil {
+		for _, device := range result {
+			skipHosts[cast.ToString(device.Protocols[OnvifProtocol][Address])] = struct{}{}
+		}
+		discovered = append(discovered, d.discoverHTTPNetscan(ctx, params, httpPorts, skipHosts)...)
+	}
 	return discovered
 }
 
+// makeNetscanTargets converts the configured DiscoveryTargets into netscan.Targets
+func makeNetscanTargets(discoveryTargets map[string]DiscoveryTarget) []netscan.Target {
+	targets := make([]netscan.Target, 0, len(discoveryTargets))
+	for _, target := range discoveryTargets {
+		targets = append(targets, netscan.Target{
+			Subnet:     target.Subnet,
+			AsyncLimit: target.ProbeAsyncLimit,
+			Timeout:    time.Duration(target.ProbeTimeoutMillis) * time.Millisecond,
+		})
+	}
+	return targets
+}
+
+// discoverHTTPNetscan runs a second netscan over tcp, which probes the onvif device service of each host
+// directly via http. This finds cameras that have WS-Discovery disabled. Hosts which were already
+// discovered via WS-Discovery, or are otherwise in skipHosts are skipped.
+func (d *Driver) discoverHTTPNetscan(ctx context.Context, params netscan.Params, ports string, skipHosts map[string]struct{}) []sdkModel.DiscoveredDevice {
+	params.ScanPorts = nil
+	for _, port := range strings.Split(ports, ",") {
+		if port = strings.TrimSpace(port); port != "" {
+			params.ScanPorts = append(params.ScanPorts, port)
+		}
+	}
+	if len(params.ScanPorts) == 0 {
+		d.lc.Warn("http netscan discovery was enabled, but HTTPDiscoveryPorts are empty!")
+		return nil
+	}
+	params.NetworkProtocol = netscan.NetworkTCP
+
+	t0 := time.Now()
+	result := netscan.AutoDiscover(ctx, NewOnvifHTTPProtocolDiscovery(d, skipHosts), params)
+	if ctx.Err() != nil {
+		d.lc.Warnf("Discover process has been cancelled!", "ctxErr", ctx.Err())
+	}
+
+	d.lc.Debugf("HTTP NetScan result: %+v", result)
+	result = preferHTTPSDevices(result)
+	d.lc.Infof("Discovered %d device(s) in %v via http netscan.", len(result), time.Since(t0))
+	return result
+}
+
 // debouncedDiscover adds or updates a future call to Discover. This function is intended to be
 // called by the config watcher in response to any configuration changes related to discovery.
 // The reason Discover calls are being debounced is to allow the user to make multiple changes to
@@ -443,17 +690,31 @@ func (d *Driver) updateWritableConfig(rawWritableConfig interface{}) {
 
 	d.configMu.Lock()
 	oldSubnets := d.config.AppCustom.DiscoverySubnets
+	oldTargets := d.config.AppCustom.DiscoveryTargets
+	credentialsChanged := updated.DefaultSecretName != d.config.AppCustom.DefaultSecretName ||
+		updated.TLSCASecretName != d.config.AppCustom.TLSCASecretName ||
+		!reflect.DeepEqual(updated.CredentialsMap, d.config.AppCustom.CredentialsMap)
 	d.config.AppCustom = *updated
 	d.configMu.Unlock()
 
-	if updated.DiscoverySubnets != oldSubnets {
+	if updated.DiscoverySubnets != oldSubnets || !reflect.DeepEqual(updated.DiscoveryTargets, oldTargets) {
 		d.lc.Info("Discover configuration has changed! Discovery will be triggered momentarily.")
 		d.debouncedDiscover()
 	}
 
 	d.macAddressMapper.UpdateMappings(d.config.AppCustom.CredentialsMap)
-	// check device statuses in case the credentials map was updated
-	d.checkStatuses()
+	// re-index the secret names of the devices in case the credentials map or TLSCASecretName was updated
+	d.indexDeviceSecrets(d.sdkService.Devices()...)
+	if credentialsChanged {
+		// the task loop checks the status of every device once woken up, rather than this callback
+		d.statusSchedule.markAllDue()
+	}
+
+	// wake up the taskLoop in case the status check configuration was updated
+	select {
+	case d.configChangedCh <- struct{}{}:
+	default:
+	}
 }
 
 // refreshDevice will attempt to retrieve the MAC address and the device info for the specified camera
@@ -530,9 +791,18 @@ func (d *Driver) refreshDevice(device models.Device) error {
 	return nil
 }
 
-// renameOrPatchDevice will call renameDevice if the device is unknown, otherwise it will call patchDeviceProtocols
+// renameOrPatchDevice will call renameDevice if the device is unknown, otherwise it will call patchDeviceProtocols.
+// When StableDeviceNames is enabled, unknown devices keep their name and have the device information patched
+// into their labels instead.
 func (d *Driver) renameOrPatchDevice(device models.Device, deviceInfo *onvifdevice.GetDeviceInformationResponse) error {
+	d.configMu.RLock()
+	stableNames := d.config.AppCustom.StableDeviceNames
+	d.configMu.RUnlock()
+
 	if strings.HasPrefix(device.Name, UnknownDevicePrefix) {
+		if stableNames {
+			return d.patchDeviceInfo(device, deviceInfo)
+		}
 		return d.renameDevice(device, deviceInfo)
 	} else {
 		return d.patchDeviceProtocols(device.Name, device.Protocols)
@@ -540,6 +810,15 @@ func (d *Driver) renameOrPatchDevice(device models.Device, deviceInfo *onvifdevi
 }
 
 func (d *Driver) renameDevice(device models.Device, deviceInfo *onvifdevice.GetDeviceInformationResponse) error {
+	name := d.buildDeviceNameForDevice(device.Protocols, deviceInfo)
+	used := make(map[string]struct{})
+	for _, existing := range d.sdkService.Devices() {
+		if existing.Name != device.Name {
+			used[existing.Name] = struct{}{}
+		}
+	}
+	name = makeUniqueDeviceName(name, cast.ToString(device.Protocols[OnvifProtocol][EndpointRefAddress]), used)
+
 	d.lc.Infof("Removing device '%s' to update device with the updated name", device.Name)
 	err := d.sdkService.RemoveDeviceByName(device.Name)
 	if err != nil {
@@ -548,17 +827,25 @@ func (d *Driver) renameDevice(device models.Device, deviceInfo *onvifdevice.GetD
 	}
 
 	device.Id = ""
-	device.Name = buildDeviceName(
-		deviceInfo.Manufacturer,
-		deviceInfo.Model,
-		cast.ToString(device.Protocols[OnvifProtocol][EndpointRefAddress]),
-	)
+	device.Name = name
 
 	d.lc.Infof("Adding device back with the updated name '%s'", device.Name)
 	_, err = d.sdkService.AddDevice(device)
 	return err
 }
 
+// patchDeviceInfo patches the protocols of a device, along with a description and labels built from the device information
+func (d *Driver) patchDeviceInfo(device models.Device, deviceInfo *onvifdevice.GetDeviceInformationResponse) error {
+	description := fmt.Sprintf("%s %s Camera", deviceInfo.Manufacturer, deviceInfo.Model)
+	d.lc.Infof("Updating the labels of device '%s' with the device information, keeping the existing name", device.Name)
+	return d.sdkService.PatchDevice(dtos.UpdateDevice{
+		Name:        &device.Name,
+		Description: &description,
+		Labels:      mergeLabels(device.Labels, deviceInfo.Manufacturer, deviceInfo.Model),
+		Protocols:   dtos.FromProtocolModelsToDTOs(device.Protocols),
+	})
+}
+
 func (d *Driver) patchDeviceProtocols(deviceName string, protocols map[string]models.ProtocolProperties) error {
 	return d.sdkService.PatchDevice(dtos.UpdateDevice{
 		Name:      &deviceName,
@@ -246,8 +246,11 @@ func TestDriver_HandleReadCommands(t *testing.T) {
 func TestRenameOrPatchDevice(t *testing.T) {
 	driver, mockService := createDriverWithMockService()
 	tests := []struct {
-		device  models.Device
-		devInfo *device.GetDeviceInformationResponse
+		device          models.Device
+		devInfo         *device.GetDeviceInformationResponse
+		nameTemplate    DeviceNameTemplate
+		stableNames     bool
+		existingDevices []models.Device
 
 		expectedDevice           models.Device
 		errorExpected            bool
@@ -296,6 +299,50 @@ func TestRenameOrPatchDevice(t *testing.T) {
 				},
 			},
 		},
+		{
+			removeDeviceExpected: true,
+			addDeviceExpected:    true,
+			nameTemplate:         "{{.Location}}-{{.Model}}-{{.SerialNumber}}",
+			existingDevices:      []models.Device{{Name: "Lobby-SimCamera-9a32410c"}},
+			device: models.Device{
+				Name: UnknownDevicePrefix + "template",
+				Protocols: map[string]models.ProtocolProperties{
+					OnvifProtocol: map[string]interface{}{
+						EndpointRefAddress: "abcd",
+						Scopes:             "onvif://www.onvif.org/location/Lobby onvif://www.onvif.org/name/Camera",
+					},
+				}},
+			devInfo: &device.GetDeviceInformationResponse{
+				Manufacturer: "Intel",
+				Model:        "SimCamera",
+				SerialNumber: "9a32410c",
+			},
+			expectedDevice: models.Device{
+				Name: "Lobby-SimCamera-9a32410c-abcd",
+				Protocols: map[string]models.ProtocolProperties{
+					OnvifProtocol: map[string]interface{}{
+						EndpointRefAddress: "abcd",
+						Scopes:             "onvif://www.onvif.org/location/Lobby onvif://www.onvif.org/name/Camera",
+					},
+				},
+			},
+		},
+		{
+			stableNames:          true,
+			updateDeviceExpected: true,
+			device: models.Device{
+				Name:   UnknownDevicePrefix + "stable",
+				Labels: []string{"auto-discovery"},
+				Protocols: map[string]models.ProtocolProperties{
+					OnvifProtocol: map[string]interface{}{
+						EndpointRefAddress: "efgh",
+					},
+				}},
+			devInfo: &device.GetDeviceInformationResponse{
+				Manufacturer: "Intel",
+				Model:        "SimCamera",
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -310,7 +357,18 @@ func TestRenameOrPatchDevice(t *testing.T) {
 				}
 			}
 
-			if test.updateDeviceExpected {
+			driver.config.AppCustom.DeviceNameTemplate = test.nameTemplate
+			driver.config.AppCustom.StableDeviceNames = test.stableNames
+
+			if test.updateDeviceExpected && test.stableNames {
+				description := "Intel SimCamera Camera"
+				mockService.On("PatchDevice", dtos.UpdateDevice{
+					Name:        &test.device.Name,
+					Description: &description,
+					Labels:      []string{"auto-discovery", "Intel", "SimCamera"},
+					Protocols:   dtos.FromProtocolModelsToDTOs(test.device.Protocols),
+				}).Return(nil).Once()
+			} else if test.updateDeviceExpected {
 				mockService.On("PatchDevice", dtos.UpdateDevice{
 					Name:      &test.device.Name,
 					Protocols: dtos.FromProtocolModelsToDTOs(test.device.Protocols),
@@ -318,6 +376,7 @@ func TestRenameOrPatchDevice(t *testing.T) {
 			}
 
 			if test.addDeviceExpected {
+				mockService.On("Devices").Return(test.existingDevices).Once()
 				mockService.On("AddDevice", test.expectedDevice).Return(test.expectedDevice.Name, nil).Once()
 			}
 
@@ -351,3 +410,24 @@ func TestDriver_RemoveDevice(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, driver.onvifClients, 0)
 }
+
+func TestDriver_initializeOnvifClients(t *testing.T) {
+	driver, _ := createDriverWithMockService()
+	driver.config = &ServiceConfig{AppCustom: CustomConfig{StatusCheckWorkers: 2}}
+
+	var devices []models.Device
+	for _, name := range []string{"camera1", "camera2", "camera3"} {
+		driver.onvifClients[name] = &OnvifClient{DeviceName: name}
+		devices = append(devices, models.Device{Name: name, Protocols: map[string]models.ProtocolProperties{OnvifProtocol: {}}})
+	}
+	// the client of a camera without an address cannot be created
+	devices = append(devices[:1], append([]models.Device{{Name: "no address",
+		Protocols: map[string]models.ProtocolProperties{OnvifProtocol: {}}}}, devices[1:]...)...)
+
+	initialized := driver.initializeOnvifClients(devices)
+	require.Len(t, initialized, 3)
+	for i, name := range []string{"camera1", "camera2", "camera3"} {
+		assert.Equal(t, name, initialized[i].Name)
+	}
+	assert.Empty(t, driver.initializeOnvifClients(nil))
+}
@@ -0,0 +1,310 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/v4/pkg/interfaces"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/labstack/echo/v4"
+	"github.com/spf13/cast"
+)
+
+const (
+	DuplicatesRestPath      = "duplicates"
+	apiDuplicatesRoute      = common.ApiBase + "/" + DuplicatesRestPath
+	apiMergeDuplicatesRoute = apiDuplicatesRoute + "/merge"
+)
+
+// DuplicateGroup is a set of registered devices which refer to the same camera
+type DuplicateGroup struct {
+	// Keep is the name of the oldest device of the group, which is kept when the group is merged
+	Keep string
+	// Duplicates are the names of the other devices of the group, which are removed when the group is merged
+	Duplicates []string
+	// Matches are the protocol properties shared by the devices, such as MACAddress=aa:bb:cc:dd:ee:ff
+	Matches []string
+}
+
+// MergeResult is the outcome of merging a DuplicateGroup
+type MergeResult struct {
+	DuplicateGroup
+	Merged bool
+	Error  string `json:",omitempty"`
+}
+
+// placeholderSerialNumbers are the serial numbers reported by cameras which were not given a real one
+var placeholderSerialNumbers = []string{"unknown", "none", "null", "n/a", "na", "default", "serial", "serialnumber",
+	"0123456789", "123456789", "1234567890"}
+
+// isPlaceholderSerialNumber returns true if the serial number is a placeholder, such as "0", "00000000", "12345" or
+// "unknown", which may be shared by distinct cameras of the same model
+func isPlaceholderSerialNumber(serialNumber string) bool {
+	serialNumber = strings.ToLower(strings.TrimSpace(serialNumber))
+	if serialNumber == "" || slices.Contains(placeholderSerialNumbers, serialNumber) {
+		return true
+	}
+	// a single repeated character, such as "0" or "ffffffff"
+	if strings.Trim(serialNumber, serialNumber[:1]) == "" {
+		return true
+	}
+	// a prefix of "123456789", such as "1234" or "12345"
+	return strings.HasPrefix("123456789", serialNumber)
+}
+
+// duplicateKeys returns the keys used to match a device against the other registered devices. A camera is identified
+// by its MAC address, its serial number and model, or its EndpointRefAddress. Placeholder serial numbers are ignored,
+// since they do not identify a camera.
+func duplicateKeys(device models.Device) []string {
+	protocol := device.Protocols[OnvifProtocol]
+	var keys []string
+	if mac, err := SanitizeMACAddress(cast.ToString(protocol[MACAddress])); err == nil {
+		keys = append(keys, MACAddress+"="+mac)
+	}
+	serialNumber := cast.ToString(protocol[SerialNumber])
+	model := cast.ToString(protocol[Model])
+	if !isPlaceholderSerialNumber(serialNumber) && model != "" {
+		keys = append(keys, SerialNumber+"+"+Model+"="+serialNumber+"+"+model)
+	}
+	if endpointRef := cast.ToString(protocol[EndpointRefAddress]); endpointRef != "" {
+		keys = append(keys, EndpointRefAddress+"="+endpointRef)
+	}
+	return keys
+}
+
+// findDuplicateDevices groups the onvif devices which share a MAC address, serial number and model, or
+// EndpointRefAddress. Devices are grouped transitively, so a device matching two others by different keys joins
+// both into a single group. The oldest device of each group is the one to keep.
+func findDuplicateDevices(devices []models.Device) []DuplicateGroup {
+	devices = slices.DeleteFunc(slices.Clone(devices), func(device models.Device) bool {
+		_, ok := device.Protocols[OnvifProtocol]
+		return !ok
+	})
+	slices.SortStableFunc(devices, compareDeviceAge)
+
+	// parent links each device index to another device index of the same group (union-find)
+	parent := make([]int, len(devices))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+
+	owners := make(map[string]int)
+	matches := make(map[string][]int)
+	for i, device := range devices {
+		for _, key := range duplicateKeys(device) {
+			owner, found := owners[key]
+			if !found {
+				owners[key] = i
+				continue
+			}
+			matches[key] = append(matches[key], i)
+			// the older device is always the root, since devices are sorted by age
+			a, b := root(owner), root(i)
+			if a < b {
+				parent[b] = a
+			} else if b < a {
+				parent[a] = b
+			}
+		}
+	}
+
+	groups := make(map[int]*DuplicateGroup)
+	var order []int
+	for i, device := range devices {
+		r := root(i)
+		if r == i {
+			continue
+		}
+		group, found := groups[r]
+		if !found {
+			group = &DuplicateGroup{Keep: devices[r].Name}
+			groups[r] = group
+			order = append(order, r)
+		}
+		group.Duplicates = append(group.Duplicates, device.Name)
+	}
+	for key, indexes := range matches {
+		group := groups[root(indexes[0])]
+		group.Matches = append(group.Matches, key)
+	}
+
+	result := make([]DuplicateGroup, 0, len(order))
+	for _, r := range order {
+		slices.Sort(groups[r].Matches)
+		result = append(result, *groups[r])
+	}
+	return result
+}
+
+// compareDeviceAge orders devices from the oldest to the newest, using the name to break ties
+func compareDeviceAge(a, b models.Device) int {
+	if a.Created != b.Created {
+		if a.Created < b.Created {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a.Name, b.Name)
+}
+
+// mergedLocationKeys are the onvif protocol properties locating the camera, which are taken together from the most
+// recently seen device of a duplicate group
+var mergedLocationKeys = []string{Address, Port, XAddrs, Scheme, DeviceServicePath}
+
+// mergedDiscoveryKeys are the onvif protocol properties learned by the discovery, which are taken from the most recently
+// seen device of a duplicate group when it knows them
+var mergedDiscoveryKeys = []string{EndpointRefAddress, LastSeen, MACAddress}
+
+// mergedOnvifProtocol returns the onvif protocol properties of the device to keep after merging the duplicates into it.
+// The network location and discovery properties are taken from the most recently seen device of the group, since the
+// duplicate was usually registered because the camera moved. All the other properties, such as the SecretName,
+// CredentialGroup, FriendlyName and capabilities, are kept from the device to keep.
+func mergedOnvifProtocol(keep models.Device, duplicates []models.Device) models.ProtocolProperties {
+	latest := keep
+	for _, duplicate := range duplicates {
+		if lastSeen(duplicate).After(lastSeen(latest)) {
+			latest = duplicate
+		}
+	}
+
+	merged := maps.Clone(keep.Protocols[OnvifProtocol])
+	latestProtocol := latest.Protocols[OnvifProtocol]
+	if cast.ToString(latestProtocol[Address]) != "" {
+		for _, key := range mergedLocationKeys {
+			if value, ok := latestProtocol[key]; ok && cast.ToString(value) != "" {
+				merged[key] = value
+			} else {
+				delete(merged, key)
+			}
+		}
+	}
+	for _, key := range mergedDiscoveryKeys {
+		if value := latestProtocol[key]; cast.ToString(value) != "" {
+			merged[key] = value
+		}
+	}
+	// keep a MAC address learned by any of the devices
+	for _, duplicate := range duplicates {
+		if cast.ToString(merged[MACAddress]) != "" {
+			break
+		}
+		if mac := cast.ToString(duplicate.Protocols[OnvifProtocol][MACAddress]); mac != "" {
+			merged[MACAddress] = mac
+		}
+	}
+	return merged
+}
+
+// mergeDuplicateGroup merges the duplicates of the group into the device to keep, which keeps its name, profile, labels
+// and custom metadata. The device to keep is updated before the duplicates are removed, so that a failed update does
+// not lose the registration of the camera.
+func (d *Driver) mergeDuplicateGroup(group DuplicateGroup) errors.EdgeX {
+	keep, err := d.sdkService.GetDeviceByName(group.Keep)
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("failed to get device '%s'", group.Keep), err)
+	}
+	duplicates := make([]models.Device, 0, len(group.Duplicates))
+	for _, name := range group.Duplicates {
+		duplicate, err := d.sdkService.GetDeviceByName(name)
+		if err != nil {
+			return errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("failed to get device '%s'", name), err)
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	keep.Protocols = maps.Clone(keep.Protocols)
+	keep.Protocols[OnvifProtocol] = mergedOnvifProtocol(keep, duplicates)
+	if err = d.sdkService.UpdateDevice(keep); err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to update device '%s'", keep.Name), err)
+	}
+
+	for _, duplicate := range duplicates {
+		if err = d.sdkService.RemoveDeviceByName(duplicate.Name); err != nil {
+			return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to remove the duplicate device '%s'", duplicate.Name), err)
+		}
+		d.lc.Infof("Merged the duplicate device %s into the device %s", duplicate.Name, keep.Name)
+	}
+	return nil
+}
+
+// mergeDuplicateDevices merges every group of duplicate devices, and returns the outcome of each group
+func (d *Driver) mergeDuplicateDevices() []MergeResult {
+	groups := findDuplicateDevices(d.sdkService.Devices())
+	results := make([]MergeResult, 0, len(groups))
+	for _, group := range groups {
+		result := MergeResult{DuplicateGroup: group, Merged: true}
+		if edgexErr := d.mergeDuplicateGroup(group); edgexErr != nil {
+			d.lc.Errorf("Failed to merge the duplicates %v into the device %s: %s", group.Duplicates, group.Keep, edgexErr.Error())
+			result.Merged = false
+			result.Error = edgexErr.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// reconcileDuplicateDevices is the reconciliation pass run before the discovered devices are filtered. The duplicate
+// devices are merged if AutoMergeDuplicates is enabled, otherwise they are only reported.
+func (d *Driver) reconcileDuplicateDevices() {
+	d.configMu.RLock()
+	autoMerge := d.config.AppCustom.AutoMergeDuplicates
+	d.configMu.RUnlock()
+
+	if autoMerge {
+		d.mergeDuplicateDevices()
+		return
+	}
+	for _, group := range findDuplicateDevices(d.sdkService.Devices()) {
+		d.lc.Warnf("The devices %v are duplicates of the device %s, matching %v. They can be merged via %s",
+			group.Duplicates, group.Keep, group.Matches, apiMergeDuplicatesRoute)
+	}
+}
+
+// addDuplicateRoutes adds the routes for reporting and merging duplicate devices
+func (d *Driver) addDuplicateRoutes() errors.EdgeX {
+	if err := d.sdkService.AddCustomRoute(apiDuplicatesRoute, interfaces.Authenticated, d.getDuplicateDevices, http.MethodGet); err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("unable to add required route: %s: %s", apiDuplicatesRoute, err.Error()), err)
+	}
+	d.lc.Infof("Route %s added.", apiDuplicatesRoute)
+
+	if err := d.sdkService.AddCustomRoute(apiMergeDuplicatesRoute, interfaces.Authenticated, d.postMergeDuplicateDevices, http.MethodPost); err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("unable to add required route: %s: %s", apiMergeDuplicatesRoute, err.Error()), err)
+	}
+	d.lc.Infof("Route %s added.", apiMergeDuplicatesRoute)
+	return nil
+}
+
+// getDuplicateDevices returns the groups of registered devices which refer to the same camera
+func (d *Driver) getDuplicateDevices(c echo.Context) error {
+	return c.JSON(http.StatusOK, findDuplicateDevices(d.sdkService.Devices()))
+}
+
+// postMergeDuplicateDevices merges every group of duplicate devices, and returns the outcome of each group
+func (d *Driver) postMergeDuplicateDevices(c echo.Context) error {
+	results := d.mergeDuplicateDevices()
+	for _, result := range results {
+		if !result.Merged {
+			return c.JSON(http.StatusMultiStatus, results)
+		}
+	}
+	return c.JSON(http.StatusOK, results)
+}
@@ -0,0 +1,191 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createDuplicateTestDevice(name string, created int64, onvifProtocol models.ProtocolProperties) models.Device {
+	return models.Device{
+		DBTimestamp: models.DBTimestamp{Created: created},
+		Name:        name,
+		Protocols: map[string]models.ProtocolProperties{
+			OnvifProtocol:  onvifProtocol,
+			CustomMetadata: {"Location": name},
+		},
+	}
+}
+
+func TestFindDuplicateDevices(t *testing.T) {
+	tests := []struct {
+		name     string
+		devices  []models.Device
+		expected []DuplicateGroup
+	}{
+		{
+			name: "no duplicates",
+			devices: []models.Device{
+				createDuplicateTestDevice("camera1", 1, models.ProtocolProperties{EndpointRefAddress: "ref1", MACAddress: "aa:bb:cc:dd:ee:01"}),
+				createDuplicateTestDevice("camera2", 2, models.ProtocolProperties{EndpointRefAddress: "ref2", MACAddress: ""}),
+				{Name: "other", Protocols: map[string]models.ProtocolProperties{"other": {}}},
+			},
+			expected: []DuplicateGroup{},
+		},
+		{
+			name: "serial number and model",
+			devices: []models.Device{
+				createDuplicateTestDevice("newer", 2, models.ProtocolProperties{EndpointRefAddress: "ref2", SerialNumber: "SN4711", Model: "cam"}),
+				createDuplicateTestDevice("older", 1, models.ProtocolProperties{EndpointRefAddress: "ref1", SerialNumber: "SN4711", Model: "cam"}),
+				createDuplicateTestDevice("other-model", 3, models.ProtocolProperties{EndpointRefAddress: "ref3", SerialNumber: "SN4711", Model: "other"}),
+			},
+			expected: []DuplicateGroup{
+				{Keep: "older", Duplicates: []string{"newer"}, Matches: []string{"SerialNumber+Model=SN4711+cam"}},
+			},
+		},
+		{
+			name: "placeholder serial numbers",
+			devices: []models.Device{
+				createDuplicateTestDevice("camera1", 1, models.ProtocolProperties{EndpointRefAddress: "ref1", SerialNumber: "0", Model: "cam"}),
+				createDuplicateTestDevice("camera2", 2, models.ProtocolProperties{EndpointRefAddress: "ref2", SerialNumber: "0", Model: "cam"}),
+				createDuplicateTestDevice("camera3", 3, models.ProtocolProperties{EndpointRefAddress: "ref3", SerialNumber: "12345", Model: "cam"}),
+				createDuplicateTestDevice("camera4", 4, models.ProtocolProperties{EndpointRefAddress: "ref4", SerialNumber: "12345", Model: "cam"}),
+				createDuplicateTestDevice("camera5", 5, models.ProtocolProperties{EndpointRefAddress: "ref5", SerialNumber: "Unknown", Model: "cam"}),
+				createDuplicateTestDevice("camera6", 6, models.ProtocolProperties{EndpointRefAddress: "ref6", SerialNumber: "unknown", Model: "cam"}),
+			},
+			expected: []DuplicateGroup{},
+		},
+		{
+			name: "transitive matches",
+			devices: []models.Device{
+				createDuplicateTestDevice("camera1", 1, models.ProtocolProperties{EndpointRefAddress: "ref1", MACAddress: "AA-BB-CC-DD-EE-01"}),
+				createDuplicateTestDevice("camera2", 2, models.ProtocolProperties{EndpointRefAddress: "ref2", MACAddress: "aa:bb:cc:dd:ee:01"}),
+				createDuplicateTestDevice("camera3", 3, models.ProtocolProperties{EndpointRefAddress: "ref2"}),
+				createDuplicateTestDevice("camera4", 4, models.ProtocolProperties{EndpointRefAddress: "ref4"}),
+				createDuplicateTestDevice("camera5", 5, models.ProtocolProperties{EndpointRefAddress: "ref4"}),
+			},
+			expected: []DuplicateGroup{
+				{Keep: "camera1", Duplicates: []string{"camera2", "camera3"}, Matches: []string{"EndpointRefAddress=ref2", "MACAddress=aa:bb:cc:dd:ee:01"}},
+				{Keep: "camera4", Duplicates: []string{"camera5"}, Matches: []string{"EndpointRefAddress=ref4"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, findDuplicateDevices(test.devices))
+		})
+	}
+}
+
+func TestIsPlaceholderSerialNumber(t *testing.T) {
+	tests := []struct {
+		serialNumber string
+		expected     bool
+	}{
+		{"", true},
+		{"0", true},
+		{"00000000", true},
+		{"FFFFFFFF", true},
+		{"1234", true},
+		{"12345", true},
+		{"123456789", true},
+		{"1234567890", true},
+		{" N/A ", true},
+		{"Unknown", true},
+		{"SN4711", false},
+		{"12354", false},
+		{"E3A7B2C10045", false},
+		{"00112233", false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.serialNumber, func(t *testing.T) {
+			assert.Equal(t, test.expected, isPlaceholderSerialNumber(test.serialNumber))
+		})
+	}
+}
+
+func TestMergeDuplicateDevices(t *testing.T) {
+	now := time.Now()
+	keep := createDuplicateTestDevice("camera1", 1, models.ProtocolProperties{
+		Address:            "192.168.1.10",
+		Port:               "80",
+		EndpointRefAddress: "ref1",
+		MACAddress:         "",
+		XAddrs:             "http://192.168.1.10/onvif/device_service",
+		FriendlyName:       "Front door",
+		SerialNumber:       "SN4711",
+		Model:              "cam",
+		FirmwareVersion:    "1.0",
+		SecretName:         "front-door",
+		CredentialGroup:    "group-a",
+		LastSeen:           now.Add(-time.Hour).Format(time.UnixDate),
+	})
+	duplicate := createDuplicateTestDevice("camera2", 2, models.ProtocolProperties{
+		Address:            "192.168.1.20",
+		Port:               "80",
+		EndpointRefAddress: "ref2",
+		MACAddress:         "aa:bb:cc:dd:ee:01",
+		FriendlyName:       "vendor cam",
+		SerialNumber:       "SN4711",
+		Model:              "cam",
+		FirmwareVersion:    "2.0",
+		SecretName:         "bogus",
+		CredentialGroup:    "group-b",
+		LastSeen:           now.Format(time.UnixDate),
+	})
+
+	driver, mockService := createDriverWithMockService()
+	mockService.On("Devices").Return([]models.Device{duplicate, keep})
+	mockService.On("GetDeviceByName", keep.Name).Return(keep, nil)
+	mockService.On("GetDeviceByName", duplicate.Name).Return(duplicate, nil)
+	mockService.On("UpdateDevice", mock.Anything).Return(nil).Once().Run(func(args mock.Arguments) {
+		updated := args.Get(0).(models.Device)
+		assert.Equal(t, keep.Name, updated.Name)
+		assert.Equal(t, keep.Protocols[CustomMetadata], updated.Protocols[CustomMetadata])
+		assert.Equal(t, "Front door", updated.Protocols[OnvifProtocol][FriendlyName])
+		assert.Equal(t, "192.168.1.20", updated.Protocols[OnvifProtocol][Address])
+		assert.Equal(t, "ref2", updated.Protocols[OnvifProtocol][EndpointRefAddress])
+		assert.Equal(t, "aa:bb:cc:dd:ee:01", updated.Protocols[OnvifProtocol][MACAddress])
+		assert.Equal(t, now.Format(time.UnixDate), updated.Protocols[OnvifProtocol][LastSeen])
+		// the location is taken as a whole, so the stale XAddrs of the kept device are dropped
+		assert.NotContains(t, updated.Protocols[OnvifProtocol], XAddrs)
+		// the other properties are kept from the device to keep
+		assert.Equal(t, "1.0", updated.Protocols[OnvifProtocol][FirmwareVersion])
+		assert.Equal(t, "front-door", updated.Protocols[OnvifProtocol][SecretName])
+		assert.Equal(t, "group-a", updated.Protocols[OnvifProtocol][CredentialGroup])
+	})
+	mockService.On("RemoveDeviceByName", duplicate.Name).Return(nil).Once()
+
+	rec := httptest.NewRecorder()
+	e := echo.New()
+	require.NoError(t, driver.postMergeDuplicateDevices(e.NewContext(httptest.NewRequest(http.MethodPost, apiMergeDuplicatesRoute, nil), rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var results []MergeResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &results))
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Merged)
+	assert.Equal(t, keep.Name, results[0].Keep)
+	assert.Equal(t, []string{duplicate.Name}, results[0].Duplicates)
+
+	// the source devices are not modified
+	assert.Equal(t, "192.168.1.10", keep.Protocols[OnvifProtocol][Address])
+	mockService.AssertExpectations(t)
+}
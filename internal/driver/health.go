@@ -0,0 +1,342 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	stdErrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	onvifdevice "github.com/IOTechSystems/onvif/device"
+	"github.com/edgexfoundry/device-sdk-go/v4/pkg/interfaces"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/labstack/echo/v4"
+	"github.com/spf13/cast"
+)
+
+const (
+	HealthRestPath = "health"
+	apiHealthRoute = common.ApiBase + "/" + HealthRestPath
+)
+
+// The kinds of errors recorded in the health of a camera
+const (
+	HealthErrorAuth        = "auth"
+	HealthErrorLocked      = "locked"
+	HealthErrorAuthBackoff = "authBackoff"
+	HealthErrorTimeout     = "timeout"
+	HealthErrorTLS         = "tls"
+	HealthErrorSOAPFault   = "soapFault"
+	HealthErrorNetwork     = "network"
+	HealthErrorOther       = "other"
+)
+
+// statusLevel returns the connection level of a device status, from 0 for Unreachable up to 3 for UpWithAuth, or -1
+// for an unknown status
+func statusLevel(status string) int {
+	switch status {
+	case Unreachable:
+		return 0
+	case Reachable:
+		return 1
+	case UpWithoutAuth:
+		return 2
+	case UpWithAuth:
+		return 3
+	}
+	return -1
+}
+
+// DeviceHealth is the health record of a camera
+type DeviceHealth struct {
+	DeviceName string
+	// Status is the status of the camera after the hysteresis thresholds, as stored in the DeviceStatus protocol property
+	Status string
+	// Level is the connection level of the Status, from 0 for Unreachable up to 3 for UpWithAuth
+	Level int
+	// ObservedStatus is the status found by the most recent check
+	ObservedStatus string `json:",omitempty"`
+	// ConsecutiveFailures is the number of consecutive checks which were unable to make an authenticated request
+	ConsecutiveFailures int
+	// ConsecutiveAuthFailures is the number of consecutive authenticated requests which the camera rejected
+	ConsecutiveAuthFailures int
+	// AuthBackoffUntil is the time until which no authenticated request is sent to the camera, after it rejected
+	// AuthFailureThreshold consecutive ones or reported a lockout
+	AuthBackoffUntil *time.Time `json:",omitempty"`
+	LastErrorKind    string     `json:",omitempty"`
+	LastError        string     `json:",omitempty"`
+	LastErrorAt      *time.Time `json:",omitempty"`
+	// LatencyMillis is the round-trip time of the last successful onvif request
+	LatencyMillis int64
+	// ClockSkewMillis is the difference between the UTC time of the camera and the UTC time of the service
+	ClockSkewMillis *int64     `json:",omitempty"`
+	LastCheck       *time.Time `json:",omitempty"`
+	LastChange      *time.Time `json:",omitempty"`
+	// LastSeen is the last time the camera was not Unreachable, which is only persisted to the LastSeen protocol
+	// property periodically
+	LastSeen *time.Time `json:",omitempty"`
+
+	// pendingStatus is an observed status which has not yet reached the hysteresis threshold, seen pendingCount times
+	pendingStatus string
+	pendingCount  int
+}
+
+// healthObservation is the outcome of a single check of a camera
+type healthObservation struct {
+	status string
+	// err is the error which prevented a higher status, if any
+	err error
+	// latency is the round-trip time of the successful onvif request, if any
+	latency time.Duration
+	// clockSkew is the time of the camera minus the time of the service, if known
+	clockSkew *time.Duration
+}
+
+// statusThresholds holds the number of consecutive checks needed to move the status of a camera up or down
+type statusThresholds struct {
+	upgrade   int
+	downgrade int
+}
+
+// deviceHealthTracker holds the health records of the cameras
+type deviceHealthTracker struct {
+	mu      sync.Mutex
+	devices map[string]*DeviceHealth
+}
+
+// observe records the outcome of a check of a camera whose current status is currentStatus, and returns the resulting
+// status. A status lower than the current one must be observed by thresholds.downgrade consecutive checks, and a
+// higher status by thresholds.upgrade consecutive checks, before the status changes.
+func (t *deviceHealthTracker) observe(deviceName string, currentStatus string, observation healthObservation,
+	thresholds statusThresholds, now time.Time) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.devices == nil {
+		t.devices = make(map[string]*DeviceHealth)
+	}
+	health, found := t.devices[deviceName]
+	if !found {
+		health = &DeviceHealth{DeviceName: deviceName, Status: currentStatus}
+		t.devices[deviceName] = health
+	}
+
+	health.ObservedStatus = observation.status
+	health.LastCheck = &now
+	if observation.status == UpWithAuth {
+		health.ConsecutiveFailures = 0
+	} else {
+		health.ConsecutiveFailures++
+	}
+	if observation.err != nil {
+		health.LastErrorKind = classifyHealthError(observation.err)
+		health.LastError = observation.err.Error()
+		health.LastErrorAt = &now
+	}
+	if observation.latency > 0 {
+		health.LatencyMillis = observation.latency.Milliseconds()
+	}
+	if observation.clockSkew != nil {
+		skew := observation.clockSkew.Milliseconds()
+		health.ClockSkewMillis = &skew
+	}
+
+	switch {
+	case statusLevel(health.Status) < 0:
+		// the status is not known yet, so there is nothing to protect from flapping
+		health.Status = observation.status
+		health.LastChange = &now
+		health.pendingStatus, health.pendingCount = "", 0
+	case observation.status == health.Status:
+		health.pendingStatus, health.pendingCount = "", 0
+	default:
+		if observation.status == health.pendingStatus {
+			health.pendingCount++
+		} else {
+			health.pendingStatus, health.pendingCount = observation.status, 1
+		}
+		threshold := thresholds.upgrade
+		if statusLevel(observation.status) < statusLevel(health.Status) {
+			threshold = thresholds.downgrade
+		}
+		if health.pendingCount >= threshold {
+			health.Status = observation.status
+			health.LastChange = &now
+			health.pendingStatus, health.pendingCount = "", 0
+		}
+	}
+	health.Level = statusLevel(health.Status)
+	return health.Status
+}
+
+// get returns a copy of the health record of the camera, and false if the camera has not been checked yet
+func (t *deviceHealthTracker) get(deviceName string) (DeviceHealth, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health, found := t.devices[deviceName]
+	if !found {
+		return DeviceHealth{}, false
+	}
+	return *health, true
+}
+
+// seen records the time the camera was last seen
+func (t *deviceHealthTracker) seen(deviceName string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.devices == nil {
+		t.devices = make(map[string]*DeviceHealth)
+	}
+	health, found := t.devices[deviceName]
+	if !found {
+		health = &DeviceHealth{DeviceName: deviceName, Level: statusLevel("")}
+		t.devices[deviceName] = health
+	}
+	health.LastSeen = &now
+}
+
+// forget removes the health record of a camera which no longer exists
+func (t *deviceHealthTracker) forget(deviceName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.devices, deviceName)
+}
+
+// classifyHealthError returns the kind of an error which occurred while checking a camera
+func classifyHealthError(err error) string {
+	var edgexErr errors.EdgeX
+	if stdErrors.As(err, &edgexErr) {
+		switch {
+		case stdErrors.Is(err, errAuthBackoff):
+			return HealthErrorAuthBackoff
+		case isLockoutError(edgexErr):
+			return HealthErrorLocked
+		case isAuthError(edgexErr):
+			return HealthErrorAuth
+		}
+	}
+
+	var netErr net.Error
+	if stdErrors.Is(err, context.DeadlineExceeded) || (stdErrors.As(err, &netErr) && netErr.Timeout()) {
+		return HealthErrorTimeout
+	}
+
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var fingerprintErr *certificateFingerprintError
+	if stdErrors.As(err, &certErr) || stdErrors.As(err, &recordErr) || stdErrors.As(err, &authorityErr) ||
+		stdErrors.As(err, &hostnameErr) || stdErrors.As(err, &invalidErr) || stdErrors.As(err, &fingerprintErr) {
+		return HealthErrorTLS
+	}
+	msg := err.Error()
+	if strings.Contains(msg, "tls:") || strings.Contains(msg, "x509:") {
+		return HealthErrorTLS
+	}
+
+	var opErr *net.OpError
+	if stdErrors.As(err, &opErr) {
+		return HealthErrorNetwork
+	}
+	// callOnvifFunction includes the fault returned by the camera in the error message
+	if strings.Contains(msg, "Onvif error:") {
+		return HealthErrorSOAPFault
+	}
+	return HealthErrorOther
+}
+
+// clockSkewFromResponse returns the difference between the UTC time of the camera, and the UTC time of the service at
+// the midpoint of the request, using a GetSystemDateAndTime response. Returns nil if the camera did not report its UTC time.
+func clockSkewFromResponse(response any, sent time.Time, received time.Time) *time.Duration {
+	dateTimeResponse, ok := response.(*onvifdevice.GetSystemDateAndTimeResponse)
+	if !ok {
+		return nil
+	}
+	utc := dateTimeResponse.SystemDateAndTime.UTCDateTime
+	if utc.Date.Year == 0 {
+		return nil
+	}
+	cameraTime := time.Date(int(utc.Date.Year), time.Month(utc.Date.Month), int(utc.Date.Day),
+		int(utc.Time.Hour), int(utc.Time.Minute), int(utc.Time.Second), 0, time.UTC)
+	skew := cameraTime.Sub(sent.Add(received.Sub(sent) / 2))
+	return &skew
+}
+
+// statusThresholds returns the configured hysteresis thresholds of the device status
+func (d *Driver) statusThresholds() statusThresholds {
+	d.configMu.RLock()
+	defer d.configMu.RUnlock()
+
+	return statusThresholds{
+		upgrade:   max(d.config.AppCustom.StatusUpgradeThreshold, 1),
+		downgrade: max(d.config.AppCustom.StatusDowngradeThreshold, 1),
+	}
+}
+
+// deviceHealth returns the health record of the device. The status of a device which has not been checked yet is taken
+// from its protocol properties, as is the LastSeen if the persisted value is more recent, such as after a discovery.
+func (d *Driver) deviceHealth(device models.Device) DeviceHealth {
+	health, found := d.health.get(device.Name)
+	if !found {
+		health = DeviceHealth{DeviceName: device.Name}
+	}
+	if health.Status == "" {
+		health.Status = cast.ToString(device.Protocols[OnvifProtocol][DeviceStatus])
+		health.Level = statusLevel(health.Status)
+	}
+	if seen := lastSeen(device); !seen.IsZero() && (health.LastSeen == nil || seen.After(*health.LastSeen)) {
+		health.LastSeen = &seen
+	}
+	return health
+}
+
+// HealthSummary is the health of all the cameras
+type HealthSummary struct {
+	// Statuses is the number of cameras with each status
+	Statuses map[string]int
+	Devices  []DeviceHealth
+}
+
+// addHealthRoutes adds the route for querying the health of all the cameras
+func (d *Driver) addHealthRoutes() errors.EdgeX {
+	if err := d.sdkService.AddCustomRoute(apiHealthRoute, interfaces.Authenticated, d.getHealthSummary, http.MethodGet); err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("unable to add required route: %s: %s", apiHealthRoute, err.Error()), err)
+	}
+	d.lc.Infof("Route %s added.", apiHealthRoute)
+	return nil
+}
+
+// getHealthSummary returns the health records of all the cameras, sorted by name
+func (d *Driver) getHealthSummary(c echo.Context) error {
+	summary := HealthSummary{Statuses: make(map[string]int), Devices: []DeviceHealth{}}
+	for _, device := range d.sdkService.Devices() {
+		if _, ok := device.Protocols[OnvifProtocol]; !ok {
+			continue
+		}
+		health := d.deviceHealth(device)
+		summary.Statuses[health.Status]++
+		summary.Devices = append(summary.Devices, health)
+	}
+	slices.SortFunc(summary.Devices, func(a, b DeviceHealth) int { return strings.Compare(a.DeviceName, b.DeviceName) })
+	return c.JSON(http.StatusOK, summary)
+}
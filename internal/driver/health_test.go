@@ -0,0 +1,166 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	onvifdevice "github.com/IOTechSystems/onvif/device"
+	"github.com/IOTechSystems/onvif/xsd"
+	xsdonvif "github.com/IOTechSystems/onvif/xsd/onvif"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceHealthTracker_observe(t *testing.T) {
+	thresholds := statusThresholds{upgrade: 1, downgrade: 2}
+	timeout := errors.NewCommonEdgeX(errors.KindServerError, "failed to send the request",
+		&net.OpError{Op: "dial", Err: context.DeadlineExceeded})
+
+	tests := []struct {
+		name         string
+		current      string
+		observations []string
+		expected     []string
+	}{
+		{
+			name:         "unknown status is accepted immediately",
+			observations: []string{Reachable},
+			expected:     []string{Reachable},
+		},
+		{
+			name:         "single timeout does not flap",
+			current:      UpWithAuth,
+			observations: []string{Unreachable, UpWithAuth, Unreachable, UpWithAuth},
+			expected:     []string{UpWithAuth, UpWithAuth, UpWithAuth, UpWithAuth},
+		},
+		{
+			name:         "downgrade after consecutive failures",
+			current:      UpWithAuth,
+			observations: []string{Unreachable, Unreachable, Unreachable},
+			expected:     []string{UpWithAuth, Unreachable, Unreachable},
+		},
+		{
+			name:         "different lower statuses restart the count",
+			current:      UpWithAuth,
+			observations: []string{Unreachable, Reachable, Reachable},
+			expected:     []string{UpWithAuth, UpWithAuth, Reachable},
+		},
+		{
+			name:         "upgrade immediately",
+			current:      Unreachable,
+			observations: []string{UpWithoutAuth, UpWithAuth},
+			expected:     []string{UpWithoutAuth, UpWithAuth},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			tracker := deviceHealthTracker{}
+			now := time.Now()
+			for i, observed := range test.observations {
+				observation := healthObservation{status: observed}
+				if observed != UpWithAuth {
+					observation.err = timeout
+				}
+				status := tracker.observe(testDeviceName, test.current, observation, thresholds, now.Add(time.Duration(i)*time.Second))
+				assert.Equal(t, test.expected[i], status, "observation %d", i)
+			}
+		})
+	}
+}
+
+func TestDeviceHealthTracker_record(t *testing.T) {
+	tracker := deviceHealthTracker{}
+	thresholds := statusThresholds{upgrade: 1, downgrade: 2}
+	now := time.Now()
+	skew := 3 * time.Second
+
+	tracker.observe(testDeviceName, UpWithAuth, healthObservation{status: UpWithAuth, latency: 40 * time.Millisecond}, thresholds, now)
+	tracker.observe(testDeviceName, UpWithAuth, healthObservation{
+		status:    UpWithoutAuth,
+		err:       errors.NewCommonEdgeX(errors.KindInvalidId, "failed to verify the authentication", nil),
+		latency:   20 * time.Millisecond,
+		clockSkew: &skew,
+	}, thresholds, now.Add(time.Second))
+
+	health, found := tracker.get(testDeviceName)
+	require.True(t, found)
+	assert.Equal(t, UpWithAuth, health.Status)
+	assert.Equal(t, 3, health.Level)
+	assert.Equal(t, UpWithoutAuth, health.ObservedStatus)
+	assert.Equal(t, 1, health.ConsecutiveFailures)
+	assert.Equal(t, HealthErrorAuth, health.LastErrorKind)
+	assert.Equal(t, int64(20), health.LatencyMillis)
+	require.NotNil(t, health.ClockSkewMillis)
+	assert.Equal(t, int64(3000), *health.ClockSkewMillis)
+	assert.Nil(t, health.LastChange)
+
+	tracker.observe(testDeviceName, UpWithAuth, healthObservation{status: UpWithoutAuth}, thresholds, now.Add(2*time.Second))
+	health, _ = tracker.get(testDeviceName)
+	assert.Equal(t, UpWithoutAuth, health.Status)
+	assert.Equal(t, 2, health.ConsecutiveFailures)
+	require.NotNil(t, health.LastChange)
+	assert.Equal(t, now.Add(2*time.Second), *health.LastChange)
+
+	tracker.forget(testDeviceName)
+	_, found = tracker.get(testDeviceName)
+	assert.False(t, found)
+}
+
+func TestClassifyHealthError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"auth", errors.NewCommonEdgeX(errors.KindInvalidId, "failed to verify the authentication", nil), HealthErrorAuth},
+		{"locked", errors.NewCommonEdgeX(errors.KindServiceLocked, "too many requests", nil), HealthErrorLocked},
+		{"auth backoff", errors.NewCommonEdgeX(errors.KindServiceLocked, "suspended", errAuthBackoff), HealthErrorAuthBackoff},
+		{"timeout", errors.NewCommonEdgeX(errors.KindServerError, "failed to send the request",
+			fmt.Errorf("post: %w", context.DeadlineExceeded)), HealthErrorTimeout},
+		{"tls", errors.NewCommonEdgeX(errors.KindServerError, "failed to send the request",
+			x509.UnknownAuthorityError{}), HealthErrorTLS},
+		{"network", errors.NewCommonEdgeX(errors.KindServerError, "failed to send the request",
+			&net.OpError{Op: "dial", Err: fmt.Errorf("connection refused")}), HealthErrorNetwork},
+		{"soap fault", errors.NewCommonEdgeX(errors.KindServerError,
+			"failed to execute the request. Onvif error: ter:ActionNotSupported", nil), HealthErrorSOAPFault},
+		{"other", fmt.Errorf("unexpected"), HealthErrorOther},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, classifyHealthError(test.err))
+		})
+	}
+}
+
+func TestClockSkewFromResponse(t *testing.T) {
+	sent := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	received := sent.Add(2 * time.Second)
+	response := &onvifdevice.GetSystemDateAndTimeResponse{SystemDateAndTime: xsdonvif.SystemDateTime{
+		UTCDateTime: xsd.DateTime{
+			Date: xsd.Date{Year: 2026, Month: 3, Day: 1},
+			Time: xsd.Time{Hour: 12, Minute: 1, Second: 1},
+		},
+	}}
+
+	skew := clockSkewFromResponse(response, sent, received)
+	require.NotNil(t, skew)
+	assert.Equal(t, time.Minute, *skew)
+
+	assert.Nil(t, clockSkewFromResponse(&onvifdevice.GetSystemDateAndTimeResponse{}, sent, received))
+	assert.Nil(t, clockSkewFromResponse(nil, sent, received))
+}
@@ -0,0 +1,92 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+)
+
+// pendingLastSeen holds the devices whose LastSeen is due to be persisted by the next batch of updates
+type pendingLastSeen struct {
+	mu      sync.Mutex
+	devices map[string]struct{}
+}
+
+// add queues the LastSeen of the device to be persisted
+func (p *pendingLastSeen) add(deviceName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.devices == nil {
+		p.devices = make(map[string]struct{})
+	}
+	p.devices[deviceName] = struct{}{}
+}
+
+// drain returns the queued devices sorted by name, and empties the queue
+func (p *pendingLastSeen) drain() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	names := make([]string, 0, len(p.devices))
+	for name := range p.devices {
+		names = append(names, name)
+	}
+	p.devices = nil
+	slices.Sort(names)
+	return names
+}
+
+// lastSeenPersistConfig returns the configured interval at which the LastSeen of a device is persisted when nothing else
+// about the device changed, and if those updates are batched
+func (d *Driver) lastSeenPersistConfig() (time.Duration, bool) {
+	d.configMu.RLock()
+	defer d.configMu.RUnlock()
+
+	return time.Duration(d.config.AppCustom.LastSeenPersistInterval) * time.Second, d.config.AppCustom.BatchLastSeenUpdates
+}
+
+// deviceLastSeen returns the last time the device was seen, using the in-memory value if it is more recent than the
+// value persisted in the LastSeen protocol property
+func (d *Driver) deviceLastSeen(device models.Device) time.Time {
+	seen := lastSeen(device)
+	if health, found := d.health.get(device.Name); found && health.LastSeen != nil && health.LastSeen.After(seen) {
+		return *health.LastSeen
+	}
+	return seen
+}
+
+// flushLastSeenUpdates persists the LastSeen of the devices queued since the last flush
+func (d *Driver) flushLastSeenUpdates() {
+	for _, deviceName := range d.pendingLastSeen.drain() {
+		health, found := d.health.get(deviceName)
+		if !found || health.LastSeen == nil {
+			continue
+		}
+		// lookup device from cache to ensure we are updating the latest version
+		device, err := d.sdkService.GetDeviceByName(deviceName)
+		if err != nil {
+			d.lc.Debugf("Skipping the LastSeen update of device %s: %s", deviceName, err.Error())
+			continue
+		}
+		if !health.LastSeen.After(lastSeen(device)) {
+			continue
+		}
+		device.Protocols[OnvifProtocol][LastSeen] = health.LastSeen.Format(time.UnixDate)
+		if err = d.sdkService.PatchDevice(dtos.UpdateDevice{
+			Name:      &deviceName,
+			Protocols: dtos.FromProtocolModelsToDTOs(device.Protocols),
+		}); err != nil {
+			d.lc.Warnf("Could not update the LastSeen of device %s: %s", deviceName, err.Error())
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateDeviceStatusAndProperties_lastSeen(t *testing.T) {
+	recently := time.Now().Add(-time.Minute).Format(time.UnixDate)
+	longAgo := time.Now().Add(-time.Hour).Format(time.UnixDate)
+
+	tests := []struct {
+		name           string
+		status         string
+		lastSeen       string
+		properties     map[string]any
+		batch          bool
+		expectPatch    bool
+		expectBatched  bool
+		expectLastSeen bool
+	}{
+		{name: "persisted recently", status: UpWithAuth, lastSeen: recently},
+		{name: "persist interval elapsed", status: UpWithAuth, lastSeen: longAgo, expectPatch: true, expectLastSeen: true},
+		{name: "status changed", status: Reachable, lastSeen: recently, expectPatch: true, expectLastSeen: true},
+		{name: "property changed", status: UpWithAuth, lastSeen: recently, properties: map[string]any{Address: "192.168.1.20"},
+			expectPatch: true, expectLastSeen: true},
+		{name: "batched", status: UpWithAuth, lastSeen: longAgo, batch: true, expectBatched: true},
+		{name: "unreachable", status: Unreachable, lastSeen: longAgo, expectPatch: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			driver, mockService := createDriverWithMockService()
+			driver.config.AppCustom.LastSeenPersistInterval = 600
+			driver.config.AppCustom.BatchLastSeenUpdates = test.batch
+			device := createTestDeviceWithProtocols(map[string]models.ProtocolProperties{
+				OnvifProtocol: {DeviceStatus: UpWithAuth, LastSeen: test.lastSeen, Address: "192.168.1.10"},
+			})
+			mockService.On("GetDeviceByName", testDeviceName).Return(device, nil).Once()
+			if test.expectPatch {
+				mockService.On("PatchDevice", mock.MatchedBy(func(update dtos.UpdateDevice) bool {
+					persisted := update.Protocols[OnvifProtocol][LastSeen] != test.lastSeen
+					return persisted == test.expectLastSeen
+				})).Return(nil).Once()
+			}
+
+			_, err := driver.updateDeviceStatusAndProperties(testDeviceName, test.status, test.properties)
+			require.NoError(t, err)
+			mockService.AssertExpectations(t)
+
+			if test.expectBatched {
+				assert.Equal(t, []string{testDeviceName}, driver.pendingLastSeen.drain())
+			} else {
+				assert.Empty(t, driver.pendingLastSeen.drain())
+			}
+			if test.status != Unreachable {
+				health, found := driver.health.get(testDeviceName)
+				require.True(t, found)
+				require.NotNil(t, health.LastSeen)
+				assert.WithinDuration(t, time.Now(), *health.LastSeen, time.Minute)
+			}
+		})
+	}
+}
+
+func TestDriver_flushLastSeenUpdates(t *testing.T) {
+	driver, mockService := createDriverWithMockService()
+	seen := time.Now().Truncate(time.Second)
+	device := createTestDeviceWithProtocols(map[string]models.ProtocolProperties{
+		OnvifProtocol: {DeviceStatus: UpWithAuth, LastSeen: seen.Add(-time.Hour).Format(time.UnixDate)},
+	})
+
+	driver.health.seen(testDeviceName, seen)
+	driver.pendingLastSeen.add(testDeviceName)
+	driver.pendingLastSeen.add(testDeviceName)
+	driver.pendingLastSeen.add("removed")
+
+	mockService.On("GetDeviceByName", testDeviceName).Return(device, nil).Once()
+	mockService.On("PatchDevice", mock.MatchedBy(func(update dtos.UpdateDevice) bool {
+		return update.Protocols[OnvifProtocol][LastSeen] == seen.Format(time.UnixDate)
+	})).Return(nil).Once()
+
+	driver.flushLastSeenUpdates()
+	mockService.AssertExpectations(t)
+	assert.Empty(t, driver.pendingLastSeen.drain())
+	assert.True(t, seen.Equal(driver.deviceLastSeen(device)))
+}
@@ -20,6 +20,10 @@ type MACAddressMapper struct {
 	credsMu sync.RWMutex
 	// credsMap is a map between mac address to secretName
 	credsMap map[string]string
+	// configured contains the mac addresses which are mapped to a secretName by the CredentialsMap
+	configured map[string]struct{}
+	// learned is a map between mac address to the secretName which was found to work by a credential trial
+	learned map[string]string
 
 	sdkService interfaces.DeviceServiceSDK
 }
@@ -28,6 +32,8 @@ type MACAddressMapper struct {
 func NewMACAddressMapper(sdkService interfaces.DeviceServiceSDK) *MACAddressMapper {
 	return &MACAddressMapper{
 		credsMap:   make(map[string]string),
+		configured: make(map[string]struct{}),
+		learned:    make(map[string]string),
 		sdkService: sdkService,
 	}
 }
@@ -64,7 +70,39 @@ func (m *MACAddressMapper) UpdateMappings(raw map[string]string) {
 		}
 	}
 
+	configured := make(map[string]struct{}, len(credsMap))
+	for mac := range credsMap {
+		configured[mac] = struct{}{}
+	}
+	// the learned mappings are kept, unless the mac address has since been configured
+	for mac, secretName := range m.learned {
+		if _, found := credsMap[mac]; !found {
+			credsMap[mac] = secretName
+		}
+	}
+
 	m.credsMap = credsMap
+	m.configured = configured
+}
+
+// LearnMapping maps the mac address to the secret name which was found to work by a credential trial. Mac addresses
+// which are mapped by the CredentialsMap are not changed. Returns false if the mapping was not stored.
+func (m *MACAddressMapper) LearnMapping(mac string, secretName string) bool {
+	sanitized, err := SanitizeMACAddress(mac)
+	if err != nil {
+		m.sdkService.LoggingClient().Warnf("Unable to learn the credential group of invalid mac address '%s': %s", mac, err.Error())
+		return false
+	}
+
+	m.credsMu.Lock()
+	defer m.credsMu.Unlock()
+
+	if _, found := m.configured[sanitized]; found {
+		return false
+	}
+	m.learned[sanitized] = secretName
+	m.credsMap[sanitized] = secretName
+	return true
 }
 
 // TryGetSecretNameForMACAddress will return the secret name associated with the mac address passed if a mapping exists,
@@ -333,3 +333,32 @@ func TestTryGetSecretNameForMACAddress(t *testing.T) {
 		})
 	}
 }
+
+// TestMACAddressMapper_LearnMapping verifies learned mappings do not override configured ones, and survive configuration updates.
+func TestMACAddressMapper_LearnMapping(t *testing.T) {
+	configuredMac := "aa:bb:cc:dd:ee:ff"
+	learnedMac := "11:22:33:44:55:66"
+
+	_, mockService := createDriverWithMockService()
+	mockService.On("LoggingClient").Return(logger.NewMockClient())
+	mockSecretProvider := &mocks.SecretProvider{}
+	mockSecretProvider.On("GetSecret", "configured", UsernameKey, PasswordKey, AuthModeKey).
+		Return(map[string]string{UsernameKey: "username", PasswordKey: "password", AuthModeKey: AuthModeDigest}, nil)
+	mockService.On("SecretProvider").Return(mockSecretProvider)
+
+	macMapper := NewMACAddressMapper(mockService)
+	macMapper.UpdateMappings(map[string]string{"configured": configuredMac})
+
+	assert.False(t, macMapper.LearnMapping(configuredMac, "learned"))
+	assert.False(t, macMapper.LearnMapping("invalid_mac", "learned"))
+	assert.True(t, macMapper.LearnMapping("11-22-33-44-55-66", "learned"))
+	assert.Equal(t, "configured", macMapper.TryGetSecretNameForMACAddress(configuredMac, "default"))
+	assert.Equal(t, "learned", macMapper.TryGetSecretNameForMACAddress(learnedMac, "default"))
+
+	macMapper.UpdateMappings(map[string]string{"configured": configuredMac})
+	assert.Equal(t, "learned", macMapper.TryGetSecretNameForMACAddress(learnedMac, "default"))
+
+	// once the mac address is configured, the configured mapping wins
+	macMapper.UpdateMappings(map[string]string{"configured": configuredMac + "," + learnedMac})
+	assert.Equal(t, "configured", macMapper.TryGetSecretNameForMACAddress(learnedMac, "default"))
+}
@@ -0,0 +1,91 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+)
+
+// AllInterfaces is the DiscoveryEthernetInterface value which selects every up and non-loopback network interface
+const AllInterfaces = "all"
+
+// parseDiscoveryInterfaces splits the comma separated DiscoveryEthernetInterface value into the interface names.
+// If the value contains AllInterfaces, it is returned on its own, and an empty value returns a nil slice.
+func parseDiscoveryInterfaces(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if strings.EqualFold(name, AllInterfaces) {
+			return []string{AllInterfaces}
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// multicastInterfaceNames returns the names of the interfaces to send multicast probes on for the
+// DiscoveryEthernetInterface value. An empty value is used as is, as the single interface name.
+func (d *Driver) multicastInterfaceNames(value string) ([]string, errors.EdgeX) {
+	names := parseDiscoveryInterfaces(value)
+	if len(names) == 0 {
+		return []string{strings.TrimSpace(value)}, nil
+	}
+	ifaces, edgexErr := d.lookupInterfaces(names)
+	if edgexErr != nil {
+		return nil, errors.NewCommonEdgeXWrapper(edgexErr)
+	}
+	ifaceNames := make([]string, 0, len(ifaces))
+	for _, iface := range ifaces {
+		ifaceNames = append(ifaceNames, iface.Name)
+	}
+	return ifaceNames, nil
+}
+
+// lookupInterfaces returns the named network interfaces, or every up and non-loopback network interface if names is
+// AllInterfaces. An interface which cannot be found is logged and skipped, so that a single misspelled name does not
+// prevent the use of the others, and an error is only returned if none of the interfaces were found.
+func (d *Driver) lookupInterfaces(names []string) ([]net.Interface, errors.EdgeX) {
+	if len(names) == 1 && names[0] == AllInterfaces {
+		return upInterfaces()
+	}
+
+	ifaces := make([]net.Interface, 0, len(names))
+	for _, name := range names {
+		iface, err := net.InterfaceByName(name)
+		if err != nil {
+			d.lc.Warnf("Skipping the network interface %s which cannot be found: %s", name, err.Error())
+			continue
+		}
+		ifaces = append(ifaces, *iface)
+	}
+	if len(ifaces) == 0 {
+		return nil, errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to find any of the network interfaces %v", names), nil)
+	}
+	return ifaces, nil
+}
+
+// upInterfaces returns every up and non-loopback network interface
+func upInterfaces() ([]net.Interface, errors.EdgeX) {
+	all, err := net.Interfaces()
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindServerError, "failed to list the network interfaces", err)
+	}
+	ifaces := make([]net.Interface, 0, len(all))
+	for _, iface := range all {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		ifaces = append(ifaces, iface)
+	}
+	return ifaces, nil
+}
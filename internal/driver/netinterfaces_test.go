@@ -0,0 +1,67 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDiscoveryInterfaces(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{value: "eth0", expected: []string{"eth0"}},
+		{value: " eth1, eth2 ,", expected: []string{"eth1", "eth2"}},
+		{value: "all", expected: []string{AllInterfaces}},
+		{value: "eth0,ALL", expected: []string{AllInterfaces}},
+		{value: "", expected: nil},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.value, func(t *testing.T) {
+			assert.Equal(t, test.expected, parseDiscoveryInterfaces(test.value))
+		})
+	}
+}
+
+func TestDriver_multicastInterfaceNames(t *testing.T) {
+	ifaces, err := net.Interfaces()
+	require.NoError(t, err)
+	require.NotEmpty(t, ifaces)
+	existing := ifaces[0].Name
+
+	tests := []struct {
+		name          string
+		value         string
+		expected      []string
+		errorExpected bool
+	}{
+		{name: "empty value is used as is", value: "", expected: []string{""}},
+		{name: "missing interface is skipped", value: "missing0," + existing, expected: []string{existing}},
+		{name: "no interface found", value: "missing0,missing1", errorExpected: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			driver, _ := createDriverWithMockService()
+			names, edgexErr := driver.multicastInterfaceNames(test.value)
+			if test.errorExpected {
+				require.Error(t, edgexErr)
+				return
+			}
+			require.NoError(t, edgexErr)
+			assert.Equal(t, test.expected, names)
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v4/clients/logger"
@@ -38,6 +39,8 @@ const (
 	SubscribeCameraEvent   = "SubscribeCameraEvent"
 	UnsubscribeCameraEvent = "UnsubscribeCameraEvent"
 	GetSnapshot            = "GetSnapshot"
+	GenerateDeviceProfile  = "GenerateDeviceProfile"
+	GetDeviceHealth        = "GetDeviceHealth"
 )
 
 // OnvifClient manages the state required to issue ONVIF requests to the specified camera
@@ -46,12 +49,20 @@ type OnvifClient struct {
 	lc          logger.LoggingClient
 	DeviceName  string
 	onvifDevice OnvifDevice
+	// serviceURL is the full url of the device service which the onvifDevice was created with
+	serviceURL string
+	// tlsSettings are the settings which the onvifDevice verifies the certificate of the camera with
+	tlsSettings deviceTLSSettings
 	// RebootNeeded indicates the camera should reboot to apply the configuration change
 	RebootNeeded bool
 	// CameraEventResource is used to send the async event to north bound
 	CameraEventResource     models.DeviceResource
 	pullPointManager        *PullPointManager
 	baseNotificationManager *BaseNotificationManager
+	// clockOffset is the skew in nanoseconds of the clock of the camera which the WS-UsernameToken is adjusted by
+	clockOffset atomic.Int64
+	// clockSyncedAt is the time in unix nanoseconds of the last attempt to synchronize the clock of the camera
+	clockSyncedAt atomic.Int64
 }
 
 // newOnvifClient returns a new OnvifClient for communicating with a single camera with all of the additional
@@ -73,20 +84,12 @@ func (d *Driver) newOnvifClientInternal(device models.Device, temporary bool) (*
 		return nil, errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to create cameraInfo for camera %s", device.Name), edgexErr)
 	}
 
-	d.configMu.Lock()
-	requestTimeout := d.config.AppCustom.RequestTimeout
-	d.configMu.Unlock()
+	tlsSettings, edgexErr := d.tlsSettingsForDevice(device.Protocols)
+	if edgexErr != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to create cameraInfo for camera %s", device.Name), edgexErr)
+	}
 
-	credentials := d.getCredentialsForDevice(device)
-	onvifDevice, err := onvif.NewDevice(onvif.DeviceParams{
-		Xaddr:    xAddr,
-		Username: credentials.Username,
-		Password: credentials.Password,
-		AuthMode: credentials.AuthMode,
-		HttpClient: &http.Client{
-			Timeout: time.Duration(requestTimeout) * time.Second,
-		},
-	})
+	onvifDevice, err := d.newOnvifDevice(xAddr, device.Protocols, d.getCredentialsForDevice(device), tlsSettings)
 	if err != nil {
 		return nil, errors.NewCommonEdgeX(errors.KindServiceUnavailable, "failed to initialize Onvif device client", err)
 	}
@@ -96,6 +99,8 @@ func (d *Driver) newOnvifClientInternal(device models.Device, temporary bool) (*
 		lc:          d.lc,
 		DeviceName:  device.Name,
 		onvifDevice: onvifDevice,
+		serviceURL:  deviceServiceURL(xAddr, device.Protocols),
+		tlsSettings: tlsSettings,
 	}
 
 	if temporary {
@@ -117,6 +122,29 @@ func (d *Driver) newOnvifClientInternal(device models.Device, temporary bool) (*
 	return client, nil
 }
 
+// newOnvifDevice creates the onvif.Device used to communicate with the camera at xAddr using the specified credentials,
+// which verifies the certificate of the camera using the tlsSettings
+func (d *Driver) newOnvifDevice(xAddr string, protocols map[string]models.ProtocolProperties, credentials Credentials,
+	tlsSettings deviceTLSSettings) (*onvif.Device, error) {
+	d.configMu.RLock()
+	requestTimeout := d.config.AppCustom.RequestTimeout
+	d.configMu.RUnlock()
+
+	tlsConfig, edgexErr := tlsSettings.tlsConfig()
+	if edgexErr != nil {
+		return nil, edgexErr
+	}
+
+	scheme, path := locationFromProtocols(protocols)
+	return onvif.NewDevice(onvif.DeviceParams{
+		Xaddr:      xAddr,
+		Username:   credentials.Username,
+		Password:   credentials.Password,
+		AuthMode:   credentials.AuthMode,
+		HttpClient: newDeviceServiceHTTPClient(xAddr, scheme, path, time.Duration(requestTimeout)*time.Second, tlsConfig),
+	})
+}
+
 // updateOnvifClient updates the internal onvifDevice of an onvif client
 func (d *Driver) updateOnvifClient(device models.Device) errors.EdgeX {
 	xAddr, edgexErr := GetCameraXAddr(device.Protocols)
@@ -129,31 +157,26 @@ func (d *Driver) updateOnvifClient(device models.Device) errors.EdgeX {
 		return edgexErr
 	}
 
+	tlsSettings, edgexErr := d.tlsSettingsForDevice(device.Protocols)
+	if edgexErr != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to create cameraInfo for camera %s", device.Name), edgexErr)
+	}
+
 	credentials := d.getCredentialsForDevice(device)
 	existingParams := onvifClient.onvifDevice.GetDeviceParams()
+	serviceURL := deviceServiceURL(xAddr, device.Protocols)
 	// check the internal parameters used when creating the onvif device vs the current ones
-	if xAddr == existingParams.Xaddr && credentials.Username == existingParams.Username &&
-		credentials.Password == existingParams.Password && credentials.AuthMode == existingParams.AuthMode {
-		// XAddr and credentials are the same, skip creating new connection
+	if xAddr == existingParams.Xaddr && serviceURL == onvifClient.serviceURL && credentials.Username == existingParams.Username &&
+		credentials.Password == existingParams.Password && credentials.AuthMode == existingParams.AuthMode &&
+		tlsSettings == onvifClient.tlsSettings {
+		// XAddr, device service url, credentials and TLS settings are the same, skip creating new connection
 		d.lc.Tracef("Skip creating new connection for un-modified device %s", device.Name)
 		return nil
 	}
 
 	d.lc.Debugf("Updating connection for modified device %s", device.Name)
 
-	d.configMu.Lock()
-	requestTimeout := d.config.AppCustom.RequestTimeout
-	d.configMu.Unlock()
-
-	onvifDevice, err := onvif.NewDevice(onvif.DeviceParams{
-		Xaddr:    xAddr,
-		Username: credentials.Username,
-		Password: credentials.Password,
-		AuthMode: credentials.AuthMode,
-		HttpClient: &http.Client{
-			Timeout: time.Duration(requestTimeout) * time.Second,
-		},
-	})
+	onvifDevice, err := d.newOnvifDevice(xAddr, device.Protocols, credentials, tlsSettings)
 	if err != nil {
 		return errors.NewCommonEdgeX(errors.KindServiceUnavailable, "failed to update Onvif device client", err)
 	}
@@ -161,6 +184,8 @@ func (d *Driver) updateOnvifClient(device models.Device) errors.EdgeX {
 	// lock the clients to prevent access while the update occurs
 	d.clientsMu.Lock()
 	onvifClient.onvifDevice = onvifDevice
+	onvifClient.serviceURL = serviceURL
+	onvifClient.tlsSettings = tlsSettings
 	d.clientsMu.Unlock()
 
 	d.checkStatusOfDevice(device)
@@ -198,17 +223,7 @@ func (d *Driver) getCameraEventResourceByDeviceName(deviceName string) (r models
 	if err != nil {
 		return r, errors.NewCommonEdgeXWrapper(err)
 	}
-	profile, err := d.sdkService.GetProfileByName(device.ProfileName)
-	if err != nil {
-		return r, errors.NewCommonEdgeXWrapper(err)
-	}
-	for _, r := range profile.DeviceResources {
-		val, ok := r.Attributes[GetFunction]
-		if ok && fmt.Sprint(val) == CameraEvent {
-			return r, nil
-		}
-	}
-	return r, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("device resource with Getfunciton '%s' not found", CameraEvent), nil)
+	return d.getDeviceResourceByGetFunction(device, CameraEvent)
 }
 
 // CallOnvifFunction send the request to the camera via onvif client
@@ -324,6 +339,39 @@ func (onvifClient *OnvifClient) callCustomFunction(resourceName, functionName st
 		if err != nil {
 			return nil, errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to create commandValue for the web service '%s' function '%s'", EdgeXWebService, functionName), err)
 		}
+	case GenerateDeviceProfile:
+		profileYAML, edgexErr := onvifClient.generateDeviceProfileYAML()
+		if edgexErr != nil {
+			return nil, errors.NewCommonEdgeXWrapper(edgexErr)
+		}
+		cv, err = sdkModel.NewCommandValue(resourceName, common.ValueTypeString, profileYAML)
+		if err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to create commandValue for the web service '%s' function '%s'", EdgeXWebService, functionName), err)
+		}
+	case GetDeviceHealth:
+		deviceName := onvifClient.DeviceName
+		device, err := onvifClient.driver.sdkService.GetDeviceByName(deviceName)
+		if err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to get device '%s'", deviceName), err)
+		}
+		cv, err = sdkModel.NewCommandValue(resourceName, common.ValueTypeObject, onvifClient.driver.deviceHealth(device))
+		if err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to create commandValue for the web service '%s' function '%s'", EdgeXWebService, functionName), err)
+		}
+	case RotateCredentials:
+		deviceName := onvifClient.DeviceName
+		device, err := onvifClient.driver.sdkService.GetDeviceByName(deviceName)
+		if err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to get device '%s'", deviceName), err)
+		}
+
+		request, edgexErr := parseRotateCredentialsRequest(data)
+		if edgexErr != nil {
+			return nil, errors.NewCommonEdgeXWrapper(edgexErr)
+		}
+		if _, edgexErr = onvifClient.driver.rotateDeviceCredentials(device, request.Password); edgexErr != nil {
+			return nil, errors.NewCommonEdgeXWrapper(edgexErr)
+		}
 	case SetFriendlyName:
 		deviceName := onvifClient.DeviceName
 		device, err := onvifClient.driver.sdkService.GetDeviceByName(deviceName)
@@ -470,7 +518,7 @@ func (onvifClient *OnvifClient) callOnvifFunction(serviceName, functionName stri
 	xmlRequestBody := string(requestBody)
 	onvifClient.lc.Debugf("SOAP Request: %v", xmlRequestBody)
 
-	servResp, err := onvifClient.onvifDevice.SendSoap(endpoint, xmlRequestBody)
+	servResp, err := onvifClient.sendSoap(endpoint, xmlRequestBody)
 	if err != nil {
 		return nil, errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to send the '%s' request for the web service '%s'", functionName, serviceName), err)
 	}
@@ -481,6 +529,13 @@ func (onvifClient *OnvifClient) callOnvifFunction(serviceName, functionName stri
 		return nil, errors.NewCommonEdgeXWrapper(err)
 	}
 
+	if servResp.StatusCode == http.StatusLocked || servResp.StatusCode == http.StatusTooManyRequests {
+		// the response of a camera which locked out the user is not necessarily a SOAP envelope
+		return nil, errors.NewCommonEdgeX(errors.KindServiceLocked,
+			fmt.Sprintf("the camera refused the function '%s' of web service '%s' with status %d, the user may be locked out",
+				functionName, serviceName, servResp.StatusCode), nil)
+	}
+
 	responseEnvelope, edgexErr := createResponse(function, rsp)
 	if edgexErr != nil {
 		// log the raw response from the camera since it will not be logged further down
@@ -490,7 +545,8 @@ func (onvifClient *OnvifClient) callOnvifFunction(serviceName, functionName stri
 	res, _ := xml.Marshal(responseEnvelope)
 	onvifClient.lc.Debugf("SOAP Response: %v", string(res))
 
-	if servResp.StatusCode == http.StatusUnauthorized {
+	if servResp.StatusCode == http.StatusUnauthorized || isNotAuthorizedFault(responseEnvelope.Body.Fault) {
+		// some cameras reject the credentials with another status, such as 400 or 500, and a NotAuthorized fault
 		return nil, errors.NewCommonEdgeX(errors.KindInvalidId,
 			fmt.Sprintf("failed to verify the authentication for the function '%s' of web service '%s'. Onvif error: %s",
 				functionName, serviceName, responseEnvelope.Body.Fault.String()), nil)
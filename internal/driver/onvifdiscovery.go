@@ -8,13 +8,15 @@
 package driver
 
 import (
+	"crypto/tls"
 	stdErrors "errors"
 	"fmt"
 	"net"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
-	"github.com/IOTechSystems/onvif"
 	wsdiscovery "github.com/IOTechSystems/onvif/ws-discovery"
 	"github.com/edgexfoundry/device-onvif-camera/internal/netscan"
 	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
@@ -32,17 +34,23 @@ const (
 // OnvifProtocolDiscovery implements netscan.ProtocolSpecificDiscovery
 type OnvifProtocolDiscovery struct {
 	driver *Driver
+	// skipHosts contains the hosts of known devices which do not need probing again
+	skipHosts map[string]struct{}
+	// tlsConfig is used to verify the certificate of the cameras advertising https XAddrs
+	tlsConfig *tls.Config
 }
 
-func NewOnvifProtocolDiscovery(driver *Driver) *OnvifProtocolDiscovery {
-	return &OnvifProtocolDiscovery{driver: driver}
+func NewOnvifProtocolDiscovery(driver *Driver, skipHosts map[string]struct{}) *OnvifProtocolDiscovery {
+	return &OnvifProtocolDiscovery{driver: driver, skipHosts: skipHosts, tlsConfig: driver.discoveryTLSConfig()}
 }
 
 // ProbeFilter takes in a host and a slice of ports to be scanned. It should return a slice
 // of ports to actually scan, or a nil/empty slice if the host is to not be scanned at all.
 // Can be used to filter out known devices from being probed again if required.
-func (proto *OnvifProtocolDiscovery) ProbeFilter(_ string, ports []string) []string {
-	// For onvif we do not want to do any filtering
+func (proto *OnvifProtocolDiscovery) ProbeFilter(host string, ports []string) []string {
+	if _, found := proto.skipHosts[host]; found {
+		return nil
+	}
 	return ports
 }
 
@@ -50,7 +58,7 @@ func (proto *OnvifProtocolDiscovery) ProbeFilter(_ string, ports []string) []str
 // a valid device or devices at the other end of the connection.
 func (proto *OnvifProtocolDiscovery) OnConnectionDialed(host string, port string, conn net.Conn, params netscan.Params) ([]netscan.ProbeResult, error) {
 	// attempt a basic direct probe approach using the open connection
-	devices, err := executeRawProbe(conn, params)
+	devices, err := executeRawProbe(conn, params, proto.tlsConfig)
 	if err != nil {
 		params.Logger.Debug(err.Error())
 	} else if len(devices) > 0 {
@@ -62,12 +70,12 @@ func (proto *OnvifProtocolDiscovery) OnConnectionDialed(host string, port string
 // ConvertProbeResult takes a raw ProbeResult and transforms it into a
 // processed DiscoveredDevice struct.
 func (proto *OnvifProtocolDiscovery) ConvertProbeResult(probeResult netscan.ProbeResult, params netscan.Params) (sdkModel.DiscoveredDevice, error) {
-	onvifDevice, ok := probeResult.Data.(onvif.Device)
+	probed, ok := probeResult.Data.(probedDevice)
 	if !ok {
-		return sdkModel.DiscoveredDevice{}, fmt.Errorf("unable to cast probe result into onvif.Device. type=%T", probeResult.Data)
+		return sdkModel.DiscoveredDevice{}, fmt.Errorf("unable to cast probe result into probedDevice. type=%T", probeResult.Data)
 	}
 
-	discovered, err := proto.driver.createDiscoveredDevice(onvifDevice)
+	discovered, err := proto.driver.createDiscoveredDevice(probed)
 	if err != nil {
 		return sdkModel.DiscoveredDevice{}, err
 	}
@@ -77,7 +85,9 @@ func (proto *OnvifProtocolDiscovery) ConvertProbeResult(probeResult netscan.Prob
 
 // createDiscoveredDevice will take an onvif.Device that was detected on the network and
 // attempt to get more information about the device and create an EdgeX compatible DiscoveredDevice.
-func (d *Driver) createDiscoveredDevice(onvifDevice onvif.Device) (sdkModel.DiscoveredDevice, error) {
+// The location is used to reach the device service of the camera at its advertised scheme and path.
+func (d *Driver) createDiscoveredDevice(probed probedDevice) (sdkModel.DiscoveredDevice, error) {
+	onvifDevice, location := probed.device, probed.location
 	xaddr := onvifDevice.GetDeviceParams().Xaddr
 	endpointRefAddr := onvifDevice.GetDeviceParams().EndpointRefAddress
 	if endpointRefAddr == "" {
@@ -103,6 +113,19 @@ func (d *Driver) createDiscoveredDevice(onvifDevice onvif.Device) (sdkModel.Disc
 			CustomMetadata: {},
 		},
 	}
+	if len(location.XAddrs) > 0 {
+		device.Protocols[OnvifProtocol][XAddrs] = strings.Join(location.XAddrs, " ")
+	}
+	if location.Scheme != "" && location.Scheme != httpScheme {
+		device.Protocols[OnvifProtocol][Scheme] = location.Scheme
+	}
+	if location.Path != "" && location.Path != deviceServicePath {
+		device.Protocols[OnvifProtocol][DeviceServicePath] = location.Path
+	}
+
+	if len(probed.scopes) > 0 {
+		device.Protocols[OnvifProtocol][Scopes] = strings.Join(probed.scopes, " ")
+	}
 
 	mac := d.macAddressMapper.MatchEndpointRefAddressToMAC(endpointRefAddr)
 	if mac != "" {
@@ -118,8 +141,15 @@ func (d *Driver) createDiscoveredDevice(onvifDevice onvif.Device) (sdkModel.Disc
 		return sdkModel.DiscoveredDevice{}, fmt.Errorf("failed to create onvif client for the camera %s", endpointRefAddr)
 	}
 
-	var discovered sdkModel.DiscoveredDevice
 	devInfo, edgexErr := onvifClient.getDeviceInformation(device)
+	if edgexErr != nil && isAuthError(edgexErr) && d.credentialTrialEnabled() {
+		if trialClient, trialDevInfo, secretName, ok := d.tryCredentialGroups(device); ok {
+			onvifClient, devInfo, edgexErr = trialClient, trialDevInfo, nil
+			device.Protocols[OnvifProtocol][CredentialGroup] = secretName
+		}
+	}
+
+	var discovered sdkModel.DiscoveredDevice
 	if edgexErr != nil {
 		d.lc.Warnf("failed to get the device information for the camera %s, %v", endpointRefAddr, edgexErr)
 		device.Protocols[OnvifProtocol][DeviceStatus] = Reachable // update device status in this error case
@@ -139,15 +169,22 @@ func (d *Driver) createDiscoveredDevice(onvifDevice onvif.Device) (sdkModel.Disc
 		device.Protocols[OnvifProtocol][DeviceStatus] = UpWithAuth
 		device.Protocols[OnvifProtocol][LastSeen] = time.Now().Format(time.UnixDate)
 		device.Protocols[OnvifProtocol][FriendlyName] = devInfo.Manufacturer + " " + devInfo.Model
-
-		deviceName := buildDeviceName(devInfo.Manufacturer, devInfo.Model, endpointRefAddr)
+		// record the optional services supported by the camera, so that provision watchers can match on them. This
+		// sends an authenticated GetServices request, so it is skipped for cameras which rejected the credentials.
+		for flag, supported := range onvifClient.getCapabilityFlags(endpointRefAddr) {
+			device.Protocols[OnvifProtocol][flag] = supported
+		}
 
 		netInfo, err := onvifClient.getNetworkInterfaces(device)
 		if err != nil {
-			d.lc.Warnf("failed to get the network information for device %s, %v", deviceName, edgexErr)
+			d.lc.Warnf("failed to get the network information for the camera %s, %v", endpointRefAddr, edgexErr)
 		} else {
 			device.Protocols[OnvifProtocol][MACAddress] = string(netInfo.NetworkInterfaces.Info.HwAddress)
 		}
+		// remember the credential group found by the credential trial, so that the camera keeps using it once added
+		d.learnCredentialGroup(device.Protocols)
+
+		deviceName := d.buildDeviceNameForDevice(device.Protocols, devInfo)
 
 		discovered = sdkModel.DiscoveredDevice{
 			Name:        deviceName,
@@ -160,9 +197,9 @@ func (d *Driver) createDiscoveredDevice(onvifDevice onvif.Device) (sdkModel.Disc
 	return discovered, nil
 }
 
-// mapProbeResults converts a slice of discovered onvif.Device into the generic
+// mapProbeResults converts a slice of discovered probedDevice into the generic
 // netscan.ProbeResult.
-func mapProbeResults(host, port string, devices []onvif.Device) (res []netscan.ProbeResult) {
+func mapProbeResults(host, port string, devices []probedDevice) (res []netscan.ProbeResult) {
 	for _, device := range devices {
 		res = append(res, netscan.ProbeResult{
 			Host: host,
@@ -175,8 +212,8 @@ func mapProbeResults(host, port string, devices []onvif.Device) (res []netscan.P
 
 // executeRawProbe essentially performs a UDP unicast ws-discovery probe by sending the
 // probe message directly over the connection and listening for any responses. Those
-// responses are then converted into a slice of onvif.Device.
-func executeRawProbe(conn net.Conn, params netscan.Params) ([]onvif.Device, error) {
+// responses are then converted into a slice of probedDevice.
+func executeRawProbe(conn net.Conn, params netscan.Params, tlsConfig *tls.Config) ([]probedDevice, error) {
 	probeSOAP := wsdiscovery.BuildProbeMessage(uuid.NewString(), nil, []string{"dn:NetworkVideoTransmitter"},
 		map[string]string{"dn": "http://www.onvif.org/ver10/network/wsdl"})
 
@@ -215,10 +252,7 @@ func executeRawProbe(conn net.Conn, params netscan.Params) ([]onvif.Device, erro
 		params.Logger.Debugf("%s: Response %d of %d: %s", addr, i+1, len(responses), resp)
 	}
 
-	devices, err := wsdiscovery.DevicesFromProbeResponses(responses)
-	if err != nil {
-		return nil, err
-	}
+	devices := devicesFromProbeResponses(responses, params.Timeout, tlsConfig, params.Logger)
 	if len(devices) == 0 {
 		params.Logger.Debugf("%s: no devices matched from probe response", addr)
 		return nil, nil
@@ -258,6 +292,21 @@ func (d *Driver) makeDeviceMacMap() map[string]contract.Device {
 	return deviceMap
 }
 
+// makeUpWithAuthHostSet creates a lookup set of the addresses of existing devices which are UpWithAuth.
+func (d *Driver) makeUpWithAuthHostSet() map[string]struct{} {
+	hosts := make(map[string]struct{})
+	for _, dev := range d.sdkService.Devices() {
+		onvifInfo, ok := dev.Protocols[OnvifProtocol]
+		if !ok || cast.ToString(onvifInfo[DeviceStatus]) != UpWithAuth {
+			continue
+		}
+		if address := cast.ToString(onvifInfo[Address]); address != "" {
+			hosts[address] = struct{}{}
+		}
+	}
+	return hosts
+}
+
 // makeDeviceRefMap creates a lookup table of existing devices by EndpointRefAddress.
 func (d *Driver) makeDeviceRefMap() map[string]contract.Device {
 	devices := d.sdkService.Devices()
@@ -294,12 +343,17 @@ func (d *Driver) discoverFilter(discoveredDevices []sdkModel.DiscoveredDevice) [
 	var discovered []sdkModel.DiscoveredDevice
 
 	// filter out newly discovered devices with the same EndpointRefAddress. This is common when using a DiscoveryMode
-	// of 'both', and the device being discovered from both modes
+	// of 'both', and the device being discovered from both modes, or when multicast is run on multiple interfaces
 	for _, device := range discoveredDevices {
 		endpointRefAddress := cast.ToString(device.Protocols[OnvifProtocol][EndpointRefAddress])
-		if _, found := discoveredMap[endpointRefAddress]; !found {
+		if first, found := discoveredMap[endpointRefAddress]; !found {
 			discoveredMap[endpointRefAddress] = device
 			discovered = append(discovered, device)
+		} else if iface := cast.ToString(device.Protocols[OnvifProtocol][DiscoveryInterface]); iface != "" {
+			// keep track of every interface the device was seen on. The protocols are shared with the
+			// device in the discovered slice, so this updates it as well.
+			first.Protocols[OnvifProtocol][DiscoveryInterface] = mergeDiscoveryInterfaces(
+				cast.ToString(first.Protocols[OnvifProtocol][DiscoveryInterface]), iface)
 		}
 	}
 
@@ -330,9 +384,32 @@ func (d *Driver) discoverFilter(discoveredDevices []sdkModel.DiscoveredDevice) [
 		filtered = append(filtered, device)
 	}
 
+	d.makeUniqueDiscoveredNames(filtered)
 	return filtered
 }
 
+// makeUniqueDiscoveredNames renames any of the discovered devices whose name is already used by an existing
+// device, or by another discovered device, as can happen when the DeviceNameTemplate does not include the
+// EndpointRefAddress.
+func (d *Driver) makeUniqueDiscoveredNames(discovered []sdkModel.DiscoveredDevice) {
+	if len(discovered) == 0 {
+		return
+	}
+
+	used := make(map[string]struct{})
+	for _, dev := range d.sdkService.Devices() {
+		used[dev.Name] = struct{}{}
+	}
+	for i := range discovered {
+		name := makeUniqueDeviceName(discovered[i].Name,
+			cast.ToString(discovered[i].Protocols[OnvifProtocol][EndpointRefAddress]), used)
+		if name != discovered[i].Name {
+			d.lc.Infof("Discovered device name '%s' is already in use, using the name '%s' instead", discovered[i].Name, name)
+			discovered[i].Name = name
+		}
+	}
+}
+
 // updateExistingDevice compares a discovered device and a matching existing device, and updates the existing
 // device network address and port if necessary
 func (d *Driver) updateExistingDevice(device contract.Device, discDev sdkModel.DiscoveredDevice) error {
@@ -341,6 +418,11 @@ func (d *Driver) updateExistingDevice(device contract.Device, discDev sdkModel.D
 		device.OperatingState = contract.Up
 		shouldUpdate = true
 	}
+	if slices.Contains(device.Labels, StaleLabel) {
+		device.Labels = slices.DeleteFunc(slices.Clone(device.Labels), func(label string) bool { return label == StaleLabel })
+		shouldUpdate = true
+	}
+	d.staleDevices.seen(device.Name, time.Now())
 
 	device.Protocols[OnvifProtocol][LastSeen] = time.Now().Format(time.UnixDate)
 
@@ -358,11 +440,49 @@ func (d *Driver) updateExistingDevice(device contract.Device, discDev sdkModel.D
 		shouldUpdate = true
 	}
 
+	// keep the device service location up to date, when it was advertised by the camera
+	if _, ok := discDev.Protocols[OnvifProtocol][XAddrs]; ok {
+		for _, key := range []string{XAddrs, Scheme, DeviceServicePath} {
+			discValue, found := discDev.Protocols[OnvifProtocol][key]
+			if device.Protocols[OnvifProtocol][key] == discValue {
+				continue
+			}
+			if found {
+				device.Protocols[OnvifProtocol][key] = discValue
+			} else {
+				delete(device.Protocols[OnvifProtocol], key)
+			}
+			shouldUpdate = true
+		}
+	}
+
 	if device.Protocols[OnvifProtocol][EndpointRefAddress] != discDev.Protocols[OnvifProtocol][EndpointRefAddress] {
 		device.Protocols[OnvifProtocol][EndpointRefAddress] = discDev.Protocols[OnvifProtocol][EndpointRefAddress]
 		shouldUpdate = true
 	}
 
+	// keep track of every interface the device was seen on, so that a camera reachable from several interfaces does
+	// not flip between them on every discovery
+	existingIfaces := cast.ToString(device.Protocols[OnvifProtocol][DiscoveryInterface])
+	if mergedIfaces := mergeDiscoveryInterfaces(existingIfaces, cast.ToString(discDev.Protocols[OnvifProtocol][DiscoveryInterface])); mergedIfaces != existingIfaces {
+		device.Protocols[OnvifProtocol][DiscoveryInterface] = mergedIfaces
+		shouldUpdate = true
+	}
+
+	// keep the capability flags up to date, leaving alone the flags which could not be determined this time
+	for _, service := range capabilityServices {
+		if supported, found := discDev.Protocols[OnvifProtocol][service.flag]; found && device.Protocols[OnvifProtocol][service.flag] != supported {
+			device.Protocols[OnvifProtocol][service.flag] = supported
+			shouldUpdate = true
+		}
+	}
+
+	discoveredGroup := cast.ToString(discDev.Protocols[OnvifProtocol][CredentialGroup])
+	if discoveredGroup != "" && cast.ToString(device.Protocols[OnvifProtocol][CredentialGroup]) != discoveredGroup {
+		device.Protocols[OnvifProtocol][CredentialGroup] = discoveredGroup
+		shouldUpdate = true
+	}
+
 	discoveredMAC := cast.ToString(discDev.Protocols[OnvifProtocol][MACAddress])
 	sanitizedMAC, macErr := SanitizeMACAddress(discoveredMAC)
 	if macErr == nil && device.Protocols[OnvifProtocol][MACAddress] != sanitizedMAC {
@@ -383,3 +503,15 @@ func (d *Driver) updateExistingDevice(device contract.Device, discDev sdkModel.D
 
 	return nil
 }
+
+// mergeDiscoveryInterfaces adds the interfaces of the comma separated added list to the comma separated
+// existing list, skipping any duplicates.
+func mergeDiscoveryInterfaces(existing string, added string) string {
+	var merged []string
+	for _, iface := range strings.Split(existing+","+added, ",") {
+		if iface != "" && !slices.Contains(merged, iface) {
+			merged = append(merged, iface)
+		}
+	}
+	return strings.Join(merged, ",")
+}
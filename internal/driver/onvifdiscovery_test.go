@@ -8,11 +8,16 @@
 package driver
 
 import (
+	"maps"
 	"testing"
 
 	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
 	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spf13/cast"
 )
 
 const (
@@ -255,6 +260,45 @@ func TestOnvifDiscovery_discoveryFilter(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "duplicate devices discovered on multiple interfaces",
+			devices: []models.Device{},
+			discoveredDevices: []sdkModel.DiscoveredDevice{
+				{
+					Name: "testDevice4", Protocols: map[string]models.ProtocolProperties{
+						OnvifProtocol: map[string]interface{}{
+							EndpointRefAddress: uuid4,
+							DiscoveryInterface: "eth1",
+						},
+					},
+				},
+				{
+					Name: "testDevice4", Protocols: map[string]models.ProtocolProperties{
+						OnvifProtocol: map[string]interface{}{
+							EndpointRefAddress: uuid4,
+							DiscoveryInterface: "eth2",
+						},
+					},
+				},
+				{
+					Name: "testDevice4", Protocols: map[string]models.ProtocolProperties{
+						OnvifProtocol: map[string]interface{}{
+							EndpointRefAddress: uuid4,
+						},
+					},
+				},
+			},
+			filtered: []sdkModel.DiscoveredDevice{
+				{
+					Name: "testDevice4", Protocols: map[string]models.ProtocolProperties{
+						OnvifProtocol: map[string]interface{}{
+							EndpointRefAddress: uuid4,
+							DiscoveryInterface: "eth1,eth2",
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, test := range tests {
 		test := test
@@ -269,3 +313,125 @@ func TestOnvifDiscovery_discoveryFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestDriver_updateExistingDevice_discoveryInterfaces(t *testing.T) {
+	tests := []struct {
+		name           string
+		existing       string
+		discovered     string
+		expected       string
+		expectedUpdate bool
+	}{
+		{name: "new interface", existing: "eth1", discovered: "eth2", expected: "eth1,eth2", expectedUpdate: true},
+		{name: "known interface", existing: "eth1,eth2", discovered: "eth2", expected: "eth1,eth2"},
+		{name: "first interface", discovered: "eth1", expected: "eth1", expectedUpdate: true},
+		{name: "no interface", existing: "eth1", expected: "eth1"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			driver, mockService := createDriverWithMockService()
+			mockService.On("UpdateDevice", mock.Anything).Return(nil).Maybe()
+
+			protocol := models.ProtocolProperties{Address: "192.168.1.10", Port: "80", EndpointRefAddress: uuid1}
+			if test.existing != "" {
+				protocol[DiscoveryInterface] = test.existing
+			}
+			device := models.Device{Name: testDeviceName, Protocols: map[string]models.ProtocolProperties{OnvifProtocol: protocol}}
+			discoveredProtocol := models.ProtocolProperties{Address: "192.168.1.10", Port: "80", EndpointRefAddress: uuid1,
+				DiscoveryInterface: test.discovered}
+			discovered := sdkModel.DiscoveredDevice{Protocols: map[string]models.ProtocolProperties{OnvifProtocol: discoveredProtocol}}
+
+			require.NoError(t, driver.updateExistingDevice(device, discovered))
+			if !test.expectedUpdate {
+				mockService.AssertNotCalled(t, "UpdateDevice", mock.Anything)
+			} else {
+				mockService.AssertCalled(t, "UpdateDevice", mock.Anything)
+			}
+			assert.Equal(t, test.expected, cast.ToString(device.Protocols[OnvifProtocol][DiscoveryInterface]))
+		})
+	}
+}
+
+func TestDriver_updateExistingDevice_capabilityFlags(t *testing.T) {
+	tests := []struct {
+		name           string
+		existing       models.ProtocolProperties
+		discovered     models.ProtocolProperties
+		expected       models.ProtocolProperties
+		expectedUpdate bool
+	}{
+		{
+			name:           "flags refreshed",
+			existing:       models.ProtocolProperties{SupportsPTZ: "false", SupportsEvents: "true"},
+			discovered:     models.ProtocolProperties{SupportsPTZ: "true", SupportsEvents: "true"},
+			expected:       models.ProtocolProperties{SupportsPTZ: "true", SupportsEvents: "true"},
+			expectedUpdate: true,
+		},
+		{
+			name:           "first flags",
+			discovered:     models.ProtocolProperties{SupportsPTZ: "true"},
+			expected:       models.ProtocolProperties{SupportsPTZ: "true"},
+			expectedUpdate: true,
+		},
+		{
+			name:       "unknown flags are kept",
+			existing:   models.ProtocolProperties{SupportsPTZ: "false", SupportsEvents: "true"},
+			discovered: models.ProtocolProperties{SupportsEvents: "true"},
+			expected:   models.ProtocolProperties{SupportsPTZ: "false", SupportsEvents: "true"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			driver, mockService := createDriverWithMockService()
+			mockService.On("UpdateDevice", mock.Anything).Return(nil).Maybe()
+
+			protocol := models.ProtocolProperties{Address: "192.168.1.10", Port: "80", EndpointRefAddress: uuid1}
+			maps.Copy(protocol, test.existing)
+			device := models.Device{Name: testDeviceName, Protocols: map[string]models.ProtocolProperties{OnvifProtocol: protocol}}
+			discoveredProtocol := models.ProtocolProperties{Address: "192.168.1.10", Port: "80", EndpointRefAddress: uuid1}
+			maps.Copy(discoveredProtocol, test.discovered)
+			discovered := sdkModel.DiscoveredDevice{Protocols: map[string]models.ProtocolProperties{OnvifProtocol: discoveredProtocol}}
+
+			require.NoError(t, driver.updateExistingDevice(device, discovered))
+			if !test.expectedUpdate {
+				mockService.AssertNotCalled(t, "UpdateDevice", mock.Anything)
+			} else {
+				mockService.AssertCalled(t, "UpdateDevice", mock.Anything)
+			}
+			for _, service := range capabilityServices {
+				assert.Equal(t, test.expected[service.flag], device.Protocols[OnvifProtocol][service.flag], service.flag)
+			}
+		})
+	}
+}
+
+func TestOnvifProtocolDiscovery_ProbeFilter(t *testing.T) {
+	driver, mockService := createDriverWithMockService()
+	mockService.On("Devices").Return([]models.Device{
+		{
+			Name: "upWithAuth", Protocols: map[string]models.ProtocolProperties{
+				OnvifProtocol: {Address: "192.168.1.10", DeviceStatus: UpWithAuth},
+			},
+		},
+		{
+			Name: "upWithoutAuth", Protocols: map[string]models.ProtocolProperties{
+				OnvifProtocol: {Address: "192.168.1.11", DeviceStatus: UpWithoutAuth},
+			},
+		},
+		{
+			Name: "noOnvifProtocol", Protocols: map[string]models.ProtocolProperties{},
+		},
+	}).Once()
+
+	proto := NewOnvifProtocolDiscovery(driver, driver.makeUpWithAuthHostSet())
+	mockService.AssertExpectations(t)
+
+	ports := []string{wsDiscoveryPort}
+	assert.Empty(t, proto.ProbeFilter("192.168.1.10", ports))
+	assert.Equal(t, ports, proto.ProbeFilter("192.168.1.11", ports))
+	assert.Equal(t, ports, proto.ProbeFilter("192.168.1.12", ports))
+}
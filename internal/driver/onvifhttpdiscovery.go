@@ -0,0 +1,232 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/IOTechSystems/onvif"
+	onvifdevice "github.com/IOTechSystems/onvif/device"
+	"github.com/IOTechSystems/onvif/gosoap"
+	"github.com/edgexfoundry/device-onvif-camera/internal/netscan"
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cast"
+)
+
+const (
+	// deviceServicePath is the well-known path of the onvif device management service
+	deviceServicePath = "/onvif/device_service"
+	// httpsPort is the scan port on which the http probe is sent over tls
+	httpsPort = "443"
+)
+
+// OnvifHTTPProtocolDiscovery implements netscan.ProtocolSpecificDiscovery for cameras which do not
+// answer WS-Discovery probes. Instead of a ws-discovery probe, an unauthenticated GetSystemDateAndTime
+// request is sent directly to the onvif device service of each host.
+type OnvifHTTPProtocolDiscovery struct {
+	driver *Driver
+	// skipHosts contains the hosts which have already been discovered by other means, and do not need probing again
+	skipHosts map[string]struct{}
+	// tlsConfig is used to verify the certificate of the cameras found on https ports
+	tlsConfig *tls.Config
+}
+
+func NewOnvifHTTPProtocolDiscovery(driver *Driver, skipHosts map[string]struct{}) *OnvifHTTPProtocolDiscovery {
+	return &OnvifHTTPProtocolDiscovery{driver: driver, skipHosts: skipHosts, tlsConfig: driver.discoveryTLSConfig()}
+}
+
+// ProbeFilter takes in a host and a slice of ports to be scanned. It should return a slice
+// of ports to actually scan, or a nil/empty slice if the host is to not be scanned at all.
+// Hosts which have already responded to WS-Discovery are not probed again.
+func (proto *OnvifHTTPProtocolDiscovery) ProbeFilter(host string, ports []string) []string {
+	if _, found := proto.skipHosts[host]; found {
+		return nil
+	}
+	return ports
+}
+
+// OnConnectionDialed handles the protocol specific verification if there is actually
+// a valid device or devices at the other end of the connection.
+func (proto *OnvifHTTPProtocolDiscovery) OnConnectionDialed(host string, port string, conn net.Conn, params netscan.Params) ([]netscan.ProbeResult, error) {
+	if err := executeHTTPProbe(host, port, conn, params); err != nil {
+		return nil, err
+	}
+	return []netscan.ProbeResult{{
+		Host: host,
+		Port: port,
+		Data: net.JoinHostPort(host, port),
+	}}, nil
+}
+
+// ConvertProbeResult takes a raw ProbeResult and transforms it into a
+// processed DiscoveredDevice struct.
+func (proto *OnvifHTTPProtocolDiscovery) ConvertProbeResult(probeResult netscan.ProbeResult, params netscan.Params) (sdkModel.DiscoveredDevice, error) {
+	xaddr, ok := probeResult.Data.(string)
+	if !ok {
+		return sdkModel.DiscoveredDevice{}, fmt.Errorf("unable to cast probe result into xaddr string. type=%T", probeResult.Data)
+	}
+
+	location := deviceServiceLocation{Scheme: httpScheme, Path: deviceServicePath}
+	if probeResult.Port == httpsPort {
+		location.Scheme = httpsScheme
+	}
+	location.XAddrs = []string{location.Scheme + "://" + xaddr + location.Path}
+
+	onvifDevice, err := onvif.NewDevice(onvif.DeviceParams{
+		Xaddr:      xaddr,
+		HttpClient: newDeviceServiceHTTPClient(xaddr, location.Scheme, location.Path, params.Timeout, proto.tlsConfig),
+	})
+	if err != nil {
+		return sdkModel.DiscoveredDevice{}, err
+	}
+
+	// the params of an onvif.Device cannot be modified, so re-create it with the resolved EndpointRefAddress
+	onvifDevice, err = onvif.NewDevice(onvif.DeviceParams{
+		Xaddr:              xaddr,
+		EndpointRefAddress: endpointRefAddressForHTTPDevice(onvifDevice, probeResult.Host, location.XAddrs[0], params),
+		HttpClient:         newDeviceServiceHTTPClient(xaddr, location.Scheme, location.Path, params.Timeout, proto.tlsConfig),
+	})
+	if err != nil {
+		return sdkModel.DiscoveredDevice{}, err
+	}
+
+	return proto.driver.createDiscoveredDevice(probedDevice{device: *onvifDevice, location: location})
+}
+
+// endpointRefAddressForHTTPDevice queries the EndpointRefAddress of a camera found via http probing,
+// since it is not advertised without a ws-discovery ProbeMatch. If the camera refuses to return it
+// without authentication, a stable uuid is derived from the host instead. The real value is then
+// stored by refreshDevice once the camera becomes UpWithAuth.
+func endpointRefAddressForHTTPDevice(onvifDevice *onvif.Device, host string, serviceURL string, params netscan.Params) string {
+	resp, err := onvifDevice.CallOnvifFunction(onvif.DeviceWebService, onvif.GetEndpointReference, nil)
+	if err == nil {
+		if endpointRef, ok := resp.(*onvifdevice.GetEndpointReferenceResponse); ok && endpointRef.GUID != "" {
+			uuidElements := strings.Split(endpointRef.GUID, ":")
+			return uuidElements[len(uuidElements)-1]
+		}
+	} else {
+		params.Logger.Debugf("Unable to query the EndpointRefAddress of the camera at %s: %s", serviceURL, err.Error())
+	}
+	return fallbackEndpointRefAddress(host)
+}
+
+// fallbackEndpointRefAddress returns the uuid used as the EndpointRefAddress of a camera which does not return its own.
+// It is derived from the host only, so that a camera answering on several ports, such as 80 and 443, is a single device.
+func fallbackEndpointRefAddress(host string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(host)).String()
+}
+
+// preferHTTPSDevices keeps a single discovered device per EndpointRefAddress, which is the first one reached using
+// https if any, so that a camera answering on both http and https ports is always registered at the same location
+func preferHTTPSDevices(devices []sdkModel.DiscoveredDevice) []sdkModel.DiscoveredDevice {
+	kept := make(map[string]int, len(devices))
+	result := make([]sdkModel.DiscoveredDevice, 0, len(devices))
+	for _, device := range devices {
+		endpointRef := cast.ToString(device.Protocols[OnvifProtocol][EndpointRefAddress])
+		i, found := kept[endpointRef]
+		switch {
+		case !found:
+			kept[endpointRef] = len(result)
+			result = append(result, device)
+		case cast.ToString(result[i].Protocols[OnvifProtocol][Scheme]) != httpsScheme &&
+			cast.ToString(device.Protocols[OnvifProtocol][Scheme]) == httpsScheme:
+			result[i] = device
+		}
+	}
+	return result
+}
+
+// executeHTTPProbe sends an unauthenticated GetSystemDateAndTime request over the open connection to the
+// onvif device service, and verifies that a valid SOAP response is returned.
+func executeHTTPProbe(host string, port string, conn net.Conn, params netscan.Params) error {
+	addr := net.JoinHostPort(host, port)
+
+	if err := conn.SetDeadline(time.Now().Add(params.Timeout)); err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("%s: failed to set read/write deadline", addr), err)
+	}
+
+	scheme := httpScheme
+	if port == httpsPort {
+		// the certificate is not verified here as no credentials are sent, and the camera is
+		// only being checked for an onvif device service
+		conn = tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: true}) // nolint: gosec
+		scheme = httpsScheme
+	}
+
+	req, err := newSystemDateAndTimeRequest(scheme + "://" + addr + deviceServicePath)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+	if err = req.Write(conn); err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("%s: failed to write http probe request", addr), err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("%s: failed to read http probe response", addr), err)
+	}
+	if err = verifySystemDateAndTimeResponse(addr, resp); err != nil {
+		return err
+	}
+
+	params.Logger.Debugf("%s: Onvif device service confirmed via http probe", addr)
+	return nil
+}
+
+// newSystemDateAndTimeRequest creates an unauthenticated GetSystemDateAndTime request to the onvif device service at url
+func newSystemDateAndTimeRequest(url string) (*http.Request, error) {
+	requestBody, err := xml.Marshal(onvifdevice.GetSystemDateAndTime{})
+	if err != nil {
+		return nil, err
+	}
+	soap := gosoap.NewEmptySOAP()
+	soap.AddStringBodyContent(string(requestBody))
+	soap.AddRootNamespaces(onvif.Xlmns)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(soap.String()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(onvif.ContentType, "application/soap+xml; charset=utf-8")
+	return req, nil
+}
+
+// verifySystemDateAndTimeResponse reads and closes the body of the response to a GetSystemDateAndTime request,
+// and verifies that it is a valid SOAP response.
+func verifySystemDateAndTimeResponse(addr string, resp *http.Response) errors.EdgeX {
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, bufSize))
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("%s: failed to read http probe response body", addr), err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("%s: unexpected http probe response status %d", addr, resp.StatusCode), nil)
+	}
+
+	response := &onvifdevice.GetSystemDateAndTimeResponse{}
+	responseEnvelope := gosoap.NewSOAPEnvelope(response)
+	if err = xml.Unmarshal(data, responseEnvelope); err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("%s: http probe response is not a valid SOAP envelope", addr), err)
+	}
+	if responseEnvelope.Body.Fault != nil || !bytes.Contains(data, []byte("GetSystemDateAndTimeResponse")) {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("%s: http probe response does not contain a GetSystemDateAndTimeResponse", addr), nil)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-onvif-camera/internal/netscan"
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	systemDateAndTimeResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<SOAP-ENV:Envelope xmlns:SOAP-ENV="http://www.w3.org/2003/05/soap-envelope" xmlns:tds="http://www.onvif.org/ver10/device/wsdl" xmlns:tt="http://www.onvif.org/ver10/schema">
+<SOAP-ENV:Body><tds:GetSystemDateAndTimeResponse><tds:SystemDateAndTime><tt:DateTimeType>NTP</tt:DateTimeType>
+<tt:UTCDateTime><tt:Time><tt:Hour>1</tt:Hour><tt:Minute>2</tt:Minute><tt:Second>3</tt:Second></tt:Time>
+<tt:Date><tt:Year>2026</tt:Year><tt:Month>1</tt:Month><tt:Day>2</tt:Day></tt:Date></tt:UTCDateTime>
+</tds:SystemDateAndTime></tds:GetSystemDateAndTimeResponse></SOAP-ENV:Body></SOAP-ENV:Envelope>`
+	soapFaultResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<SOAP-ENV:Envelope xmlns:SOAP-ENV="http://www.w3.org/2003/05/soap-envelope">
+<SOAP-ENV:Body><SOAP-ENV:Fault><SOAP-ENV:Code><SOAP-ENV:Value>SOAP-ENV:Sender</SOAP-ENV:Value></SOAP-ENV:Code>
+<SOAP-ENV:Reason><SOAP-ENV:Text>Not Authorized</SOAP-ENV:Text></SOAP-ENV:Reason></SOAP-ENV:Fault></SOAP-ENV:Body></SOAP-ENV:Envelope>`
+)
+
+func TestExecuteHTTPProbe(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		response      string
+		errorExpected bool
+	}{
+		{
+			name:     "valid onvif response",
+			status:   http.StatusOK,
+			response: systemDateAndTimeResponse,
+		},
+		{
+			name:          "soap fault",
+			status:        http.StatusOK,
+			response:      soapFaultResponse,
+			errorExpected: true,
+		},
+		{
+			name:          "not found",
+			status:        http.StatusNotFound,
+			response:      "Not Found",
+			errorExpected: true,
+		},
+		{
+			name:          "not a soap response",
+			status:        http.StatusOK,
+			response:      "<html><body>Hello World!</body></html>",
+			errorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				assert.Equal(t, http.MethodPost, request.Method)
+				assert.Equal(t, deviceServicePath, request.URL.Path)
+				writer.WriteHeader(test.status)
+				_, err := writer.Write([]byte(test.response))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+
+			u, err := url.Parse(server.URL)
+			require.NoError(t, err)
+			conn, err := net.Dial(netscan.NetworkTCP, u.Host)
+			require.NoError(t, err)
+			defer conn.Close()
+
+			params := netscan.Params{
+				Timeout: time.Second,
+				Logger:  logger.NewMockClient(),
+			}
+			err = executeHTTPProbe(u.Hostname(), u.Port(), conn, params)
+			if test.errorExpected {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestOnvifHTTPProtocolDiscovery_ProbeFilter(t *testing.T) {
+	driver, _ := createDriverWithMockService()
+	proto := NewOnvifHTTPProtocolDiscovery(driver, map[string]struct{}{"192.168.1.10": {}})
+	ports := []string{"80", "8080"}
+
+	assert.Empty(t, proto.ProbeFilter("192.168.1.10", ports))
+	assert.Equal(t, ports, proto.ProbeFilter("192.168.1.11", ports))
+}
+
+func TestFallbackEndpointRefAddress(t *testing.T) {
+	endpointRef := fallbackEndpointRefAddress("192.168.1.10")
+	_, err := uuid.Parse(endpointRef)
+	require.NoError(t, err)
+	assert.Equal(t, endpointRef, fallbackEndpointRefAddress("192.168.1.10"))
+	assert.NotEqual(t, endpointRef, fallbackEndpointRefAddress("192.168.1.11"))
+}
+
+func TestPreferHTTPSDevices(t *testing.T) {
+	discovered := func(name string, endpointRef string, scheme string) sdkModel.DiscoveredDevice {
+		protocol := models.ProtocolProperties{EndpointRefAddress: endpointRef}
+		if scheme != "" {
+			protocol[Scheme] = scheme
+		}
+		return sdkModel.DiscoveredDevice{Name: name, Protocols: map[string]models.ProtocolProperties{OnvifProtocol: protocol}}
+	}
+	camera1HTTP := discovered("camera1-80", "ref1", "")
+	camera1HTTPS := discovered("camera1-443", "ref1", httpsScheme)
+	camera2HTTP := discovered("camera2-80", "ref2", "")
+	camera2HTTP8080 := discovered("camera2-8080", "ref2", "")
+	camera3HTTPS := discovered("camera3-443", "ref3", httpsScheme)
+
+	devices := []sdkModel.DiscoveredDevice{camera1HTTP, camera2HTTP, camera3HTTPS, camera1HTTPS, camera2HTTP8080}
+	assert.Equal(t, []sdkModel.DiscoveredDevice{camera1HTTPS, camera2HTTP, camera3HTTPS}, preferHTTPSDevices(devices))
+	assert.Empty(t, preferHTTPSDevices(nil))
+}
@@ -0,0 +1,318 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/IOTechSystems/onvif"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+)
+
+// webServiceNamespaces maps the onvif web services to the namespace they are advertised with in a GetServices response
+var webServiceNamespaces = map[string]string{
+	onvif.DeviceWebService:    "http://www.onvif.org/ver10/device/wsdl",
+	onvif.MediaWebService:     "http://www.onvif.org/ver10/media/wsdl",
+	onvif.Media2WebService:    "http://www.onvif.org/ver20/media/wsdl",
+	onvif.PTZWebService:       "http://www.onvif.org/ver20/ptz/wsdl",
+	onvif.EventWebService:     "http://www.onvif.org/ver10/events/wsdl",
+	onvif.ImagingWebService:   "http://www.onvif.org/ver20/imaging/wsdl",
+	onvif.AnalyticsWebService: "http://www.onvif.org/ver20/analytics/wsdl",
+	onvif.RecordingWebService: "http://www.onvif.org/ver10/recording/wsdl",
+}
+
+// customFunctionServices maps the EdgeX custom functions to the onvif web service they depend on
+var customFunctionServices = map[string]string{
+	GetSnapshot:            onvif.MediaWebService,
+	SubscribeCameraEvent:   onvif.EventWebService,
+	UnsubscribeCameraEvent: onvif.EventWebService,
+	CameraEvent:            onvif.EventWebService,
+}
+
+// functionCapabilities maps the attributes of the GetServiceCapabilities response of each onvif web service to the
+// functions they indicate support for. The attributes are identified by their path below the Capabilities element,
+// such as System.SystemBackup.
+var functionCapabilities = map[string]map[string][]string{
+	onvif.DeviceWebService: {
+		"Network.IPFilter": {onvif.GetIPAddressFilter, onvif.SetIPAddressFilter, onvif.AddIPAddressFilter,
+			onvif.RemoveIPAddressFilter},
+		"Network.ZeroConfiguration":  {onvif.GetZeroConfiguration, onvif.SetZeroConfiguration},
+		"Network.DynDNS":             {onvif.GetDynamicDNS, onvif.SetDynamicDNS},
+		"Network.Dot11Configuration": {onvif.GetDot11Capabilities, onvif.GetDot11Status, onvif.ScanAvailableDot11Networks},
+		"Network.Dot1XConfigurations": {onvif.GetDot1XConfiguration, onvif.GetDot1XConfigurations,
+			onvif.CreateDot1XConfiguration, onvif.SetDot1XConfiguration, onvif.DeleteDot1XConfiguration},
+		"Network.HostnameFromDHCP":    {onvif.SetHostnameFromDHCP},
+		"Network.NTP":                 {onvif.GetNTP, onvif.SetNTP},
+		"Security.AccessPolicyConfig": {onvif.GetAccessPolicy, onvif.SetAccessPolicy},
+		"Security.RemoteUserHandling": {onvif.GetRemoteUser, onvif.SetRemoteUser},
+		"System.RemoteDiscovery": {onvif.GetRemoteDiscoveryMode, onvif.SetRemoteDiscoveryMode, onvif.GetDPAddresses,
+			onvif.SetDPAddresses},
+		"System.SystemBackup":        {onvif.GetSystemBackup, onvif.RestoreSystem},
+		"System.HttpSystemBackup":    {onvif.StartSystemRestore},
+		"System.SystemLogging":       {onvif.GetSystemLog},
+		"System.FirmwareUpgrade":     {onvif.UpgradeSystemFirmware},
+		"System.HttpFirmwareUpgrade": {onvif.StartFirmwareUpgrade},
+		"System.StorageConfiguration": {onvif.GetStorageConfigurations, onvif.GetStorageConfiguration,
+			onvif.CreateStorageConfiguration, onvif.SetStorageConfiguration, onvif.DeleteStorageConfiguration},
+		"System.GeoLocationEntries": {onvif.GetGeoLocation, onvif.SetGeoLocation, onvif.DeleteGeoLocation},
+	},
+	onvif.MediaWebService: {
+		"SnapshotUri":     {onvif.GetSnapshotUri, GetSnapshot},
+		"OSD":             {onvif.GetOSDs, onvif.GetOSD, onvif.GetOSDOptions, onvif.SetOSD, onvif.CreateOSD, onvif.DeleteOSD},
+		"VideoSourceMode": {onvif.GetVideoSourceModes, onvif.SetVideoSourceMode},
+		"ProfileCapabilities.MaximumNumberOfProfiles": {onvif.CreateProfile, onvif.DeleteProfile},
+		"StreamingCapabilities.RTPMulticast":          {onvif.StartMulticastStreaming, onvif.StopMulticastStreaming},
+	},
+	onvif.Media2WebService: {
+		"SnapshotUri": {onvif.GetSnapshotUri},
+		"OSD":         {onvif.GetOSDs, onvif.GetOSDOptions, onvif.SetOSD, onvif.CreateOSD, onvif.DeleteOSD},
+		"ProfileCapabilities.MaximumNumberOfProfiles": {onvif.CreateProfile, onvif.DeleteProfile},
+	},
+	onvif.PTZWebService: {
+		"GetCompatibleConfigurations": {onvif.GetCompatibleConfigurations},
+	},
+	onvif.ImagingWebService: {
+		"Presets": {onvif.GetPresets, onvif.GetCurrentPreset, onvif.SetCurrentPreset},
+	},
+	onvif.RecordingWebService: {
+		"DynamicRecordings": {onvif.CreateRecording, onvif.DeleteRecording},
+		"DynamicTracks":     {onvif.CreateTrack, onvif.DeleteTrack},
+	},
+}
+
+// generateDeviceProfileYAML inspects the camera, and returns the YAML of a device profile based on the current
+// profile of the device, which only contains the resources and commands supported by the camera.
+func (onvifClient *OnvifClient) generateDeviceProfileYAML() (string, errors.EdgeX) {
+	device, err := onvifClient.driver.sdkService.GetDeviceByName(onvifClient.DeviceName)
+	if err != nil {
+		return "", errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to get device '%s'", onvifClient.DeviceName), err)
+	}
+	profile, err := onvifClient.driver.sdkService.GetProfileByName(device.ProfileName)
+	if err != nil {
+		return "", errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to get device profile '%s'", device.ProfileName), err)
+	}
+
+	supported, capabilities, edgexErr := onvifClient.getSupportedWebServices()
+	if edgexErr != nil {
+		return "", errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to get the supported services of the camera %s", device.Name), edgexErr)
+	}
+
+	generated := tailorDeviceProfile(profile, supported, capabilities,
+		cast.ToString(device.Protocols[OnvifProtocol][Manufacturer]), cast.ToString(device.Protocols[OnvifProtocol][Model]))
+	data, err := yaml.Marshal(dtos.FromDeviceProfileModelToDTO(generated))
+	if err != nil {
+		return "", errors.NewCommonEdgeX(errors.KindServerError, "failed to marshal the generated device profile", err)
+	}
+	return string(data), nil
+}
+
+// getSupportedWebServices returns the onvif web services which are advertised by the GetServices response of the camera,
+// and which answer a GetServiceCapabilities request, along with the capabilities of each service. Services whose
+// GetServiceCapabilities function is not provided by the onvif library are only checked against the GetServices response.
+func (onvifClient *OnvifClient) getSupportedWebServices() (map[string]bool, map[string]map[string]string, errors.EdgeX) {
+	namespaces, edgexErr := onvifClient.getServiceNamespaces()
+	if edgexErr != nil {
+		return nil, nil, errors.NewCommonEdgeXWrapper(edgexErr)
+	}
+
+	supported := make(map[string]bool, len(webServiceNamespaces))
+	capabilities := make(map[string]map[string]string, len(webServiceNamespaces))
+	for service, namespace := range webServiceNamespaces {
+		if _, found := namespaces[strings.ToLower(namespace)]; !found && service != onvif.DeviceWebService {
+			continue
+		}
+		if _, err := onvif.FunctionByServiceAndFunctionName(service, onvif.GetServiceCapabilities); err == nil {
+			serviceCapabilities, edgexErr := onvifClient.getServiceCapabilities(service)
+			if edgexErr != nil {
+				onvifClient.lc.Debugf("The camera %s advertised the %s service, but failed to return its capabilities: %s",
+					onvifClient.DeviceName, service, edgexErr.Error())
+				continue
+			}
+			capabilities[service] = serviceCapabilities
+		}
+		supported[service] = true
+	}
+	return supported, capabilities, nil
+}
+
+// getServiceCapabilities sends a GetServiceCapabilities request for the web service to the camera, and returns the
+// attributes of the capabilities it reports
+func (onvifClient *OnvifClient) getServiceCapabilities(service string) (map[string]string, errors.EdgeX) {
+	function, err := onvif.FunctionByServiceAndFunctionName(service, onvif.GetServiceCapabilities)
+	if err != nil {
+		return nil, errors.NewCommonEdgeXWrapper(err)
+	}
+	request := function.Request()
+	endpoint, err := onvifClient.onvifDevice.GetEndpointByRequestStruct(request)
+	if err != nil {
+		return nil, errors.NewCommonEdgeXWrapper(err)
+	}
+	requestBody, err := xml.Marshal(request)
+	if err != nil {
+		return nil, errors.NewCommonEdgeXWrapper(err)
+	}
+
+	resp, err := onvifClient.sendSoap(endpoint, string(requestBody))
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to send the GetServiceCapabilities request for the web service '%s'", service), err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindServerError, "failed to read the GetServiceCapabilities response", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("unexpected GetServiceCapabilities response status %d", resp.StatusCode), nil)
+	}
+	return parseServiceCapabilities(data)
+}
+
+// parseServiceCapabilities returns the attributes of the Capabilities element of a GetServiceCapabilities response, and
+// of its child elements, keyed by their path below the Capabilities element, such as System.SystemBackup. The response
+// is parsed here, since the types of the onvif library do not tell an attribute set to false from a missing one.
+func parseServiceCapabilities(data []byte) (map[string]string, errors.EdgeX) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	capabilities := make(map[string]string)
+	// path holds the local names of the elements from the root of the document to the current element
+	var path []string
+	// depth is the length of the path of the Capabilities element, once found
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, errors.NewCommonEdgeX(errors.KindServerError, "the GetServiceCapabilities response has no Capabilities", nil)
+		} else if err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindServerError, "failed to parse the GetServiceCapabilities response", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			path = append(path, element.Name.Local)
+			if depth == 0 {
+				if len(path) < 2 || path[len(path)-2] != "GetServiceCapabilitiesResponse" || element.Name.Local != "Capabilities" {
+					continue
+				}
+				depth = len(path)
+			}
+			prefix := strings.Join(path[depth:], ".")
+			for _, attr := range element.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+				key := attr.Name.Local
+				if prefix != "" {
+					key = prefix + "." + key
+				}
+				capabilities[key] = strings.TrimSpace(attr.Value)
+			}
+		case xml.EndElement:
+			if depth != 0 && len(path) == depth {
+				return capabilities, nil
+			}
+			path = path[:len(path)-1]
+		}
+	}
+}
+
+// isFunctionDisabled returns true if the capabilities of the web service report that the camera does not support the
+// function, by setting the matching attribute to false or zero. Functions whose attribute is missing are supported.
+func isFunctionDisabled(capabilities map[string]map[string]string, service string, functionName string) bool {
+	for capability, functions := range functionCapabilities[service] {
+		if !slices.Contains(functions, functionName) {
+			continue
+		}
+		value, found := capabilities[service][capability]
+		return found && (strings.EqualFold(value, "false") || value == "0")
+	}
+	return false
+}
+
+// tailorDeviceProfile returns a copy of the profile with only the resources whose web service is supported, whose
+// functions are provided by the onvif library, and whose functions are not reported as unsupported by the capabilities
+// of their web service. The commands are trimmed to the remaining resources, and any commands left without resources
+// are removed.
+func tailorDeviceProfile(profile models.DeviceProfile, supported map[string]bool, capabilities map[string]map[string]string,
+	manufacturer string, model string) models.DeviceProfile {
+	generated := profile
+	generated.Id = ""
+	generated.Name = sanitizeDeviceNamePart(manufacturer + "-" + model)
+	if generated.Name == "" {
+		generated.Name = profile.Name + "-generated"
+	}
+	if manufacturer != "" {
+		generated.Manufacturer = manufacturer
+	}
+	if model != "" {
+		generated.Model = model
+	}
+	generated.Description = fmt.Sprintf("EdgeX device profile for the %s %s ONVIF camera, generated from the %s profile",
+		manufacturer, model, profile.Name)
+
+	kept := make(map[string]struct{})
+	generated.DeviceResources = nil
+	for _, resource := range profile.DeviceResources {
+		if !isResourceSupported(resource, supported, capabilities) {
+			continue
+		}
+		kept[resource.Name] = struct{}{}
+		generated.DeviceResources = append(generated.DeviceResources, resource)
+	}
+
+	generated.DeviceCommands = nil
+	for _, command := range profile.DeviceCommands {
+		var operations []models.ResourceOperation
+		for _, operation := range command.ResourceOperations {
+			if _, found := kept[operation.DeviceResource]; found {
+				operations = append(operations, operation)
+			}
+		}
+		if len(operations) == 0 {
+			continue
+		}
+		command.ResourceOperations = operations
+		generated.DeviceCommands = append(generated.DeviceCommands, command)
+	}
+	return generated
+}
+
+// isResourceSupported checks the web service and functions of a device resource against the supported services, and
+// against the capabilities of the services
+func isResourceSupported(resource models.DeviceResource, supported map[string]bool, capabilities map[string]map[string]string) bool {
+	service := cast.ToString(resource.Attributes[Service])
+	for _, functionType := range []string{GetFunction, SetFunction} {
+		functionName := cast.ToString(resource.Attributes[functionType])
+		if functionName == "" {
+			continue
+		}
+		if service == EdgeXWebService {
+			requiredService, found := customFunctionServices[functionName]
+			if found && (!supported[requiredService] || isFunctionDisabled(capabilities, requiredService, functionName)) {
+				return false
+			}
+			continue
+		}
+		if _, err := onvif.FunctionByServiceAndFunctionName(service, functionName); err != nil {
+			return false
+		}
+		if isFunctionDisabled(capabilities, service, functionName) {
+			return false
+		}
+	}
+	return service == EdgeXWebService || supported[service]
+}
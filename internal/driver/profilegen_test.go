@@ -0,0 +1,174 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/IOTechSystems/onvif"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailorDeviceProfile(t *testing.T) {
+	resource := func(name, service, getFunction string) models.DeviceResource {
+		return models.DeviceResource{
+			Name:       name,
+			Attributes: map[string]interface{}{Service: service, GetFunction: getFunction},
+		}
+	}
+	profile := models.DeviceProfile{
+		Id:   "1234",
+		Name: "onvif-camera",
+		DeviceResources: []models.DeviceResource{
+			resource("DeviceInformation", onvif.DeviceWebService, onvif.GetDeviceInformation),
+			resource("Unknown", onvif.DeviceWebService, "GetUnknownFunction"),
+			resource("MediaProfiles", onvif.MediaWebService, onvif.GetProfiles),
+			resource("PTZNodes", onvif.PTZWebService, onvif.GetNodes),
+			resource("Snapshot", EdgeXWebService, GetSnapshot),
+			resource("CameraEvent", EdgeXWebService, CameraEvent),
+			resource("FriendlyName", EdgeXWebService, GetFriendlyName),
+			resource("NTP", onvif.DeviceWebService, onvif.GetNTP),
+			resource("SystemLog", onvif.DeviceWebService, onvif.GetSystemLog),
+			resource("OSDs", onvif.MediaWebService, onvif.GetOSDs),
+		},
+		DeviceCommands: []models.DeviceCommand{
+			{
+				Name: "DeviceAndPTZ",
+				ResourceOperations: []models.ResourceOperation{
+					{DeviceResource: "DeviceInformation"},
+					{DeviceResource: "PTZNodes"},
+				},
+			},
+			{
+				Name:               "PTZOnly",
+				ResourceOperations: []models.ResourceOperation{{DeviceResource: "PTZNodes"}},
+			},
+		},
+	}
+	supported := map[string]bool{
+		onvif.DeviceWebService: true,
+		onvif.MediaWebService:  true,
+	}
+
+	capabilities := map[string]map[string]string{
+		onvif.DeviceWebService: {"Network.NTP": "0"},
+		onvif.MediaWebService:  {"OSD": "false", "SnapshotUri": "true"},
+	}
+
+	generated := tailorDeviceProfile(profile, supported, capabilities, "Acme Corp", "Cam/1")
+
+	assert.Empty(t, generated.Id)
+	assert.Equal(t, "Acme-Corp-Cam-1", generated.Name)
+	assert.Equal(t, "Acme Corp", generated.Manufacturer)
+	assert.Equal(t, "Cam/1", generated.Model)
+
+	var resourceNames []string
+	for _, r := range generated.DeviceResources {
+		resourceNames = append(resourceNames, r.Name)
+	}
+	assert.Equal(t, []string{"DeviceInformation", "MediaProfiles", "Snapshot", "FriendlyName", "SystemLog"}, resourceNames)
+
+	assert.Len(t, generated.DeviceCommands, 1)
+	assert.Equal(t, "DeviceAndPTZ", generated.DeviceCommands[0].Name)
+	assert.Equal(t, []models.ResourceOperation{{DeviceResource: "DeviceInformation"}}, generated.DeviceCommands[0].ResourceOperations)
+
+	// the source profile is not modified
+	assert.Len(t, profile.DeviceResources, 10)
+	assert.Len(t, profile.DeviceCommands[0].ResourceOperations, 2)
+}
+
+func TestIsFunctionDisabled(t *testing.T) {
+	capabilities := map[string]map[string]string{
+		onvif.DeviceWebService: {"System.SystemBackup": "false", "Network.NTP": "true"},
+		onvif.MediaWebService:  {"SnapshotUri": "0"},
+	}
+	tests := []struct {
+		name         string
+		service      string
+		functionName string
+		expected     bool
+	}{
+		{"false flag", onvif.DeviceWebService, onvif.GetSystemBackup, true},
+		{"zero flag", onvif.MediaWebService, onvif.GetSnapshotUri, true},
+		{"zero flag of custom function", onvif.MediaWebService, GetSnapshot, true},
+		{"true flag", onvif.DeviceWebService, onvif.SetNTP, false},
+		{"missing flag", onvif.DeviceWebService, onvif.GetSystemLog, false},
+		{"function without flag", onvif.DeviceWebService, onvif.GetDeviceInformation, false},
+		{"service without capabilities", onvif.Media2WebService, onvif.GetOSDs, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, isFunctionDisabled(capabilities, test.service, test.functionName))
+		})
+	}
+}
+
+func TestParseServiceCapabilities(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		expected      map[string]string
+		errorExpected bool
+	}{
+		{
+			name: "device capabilities",
+			data: `<?xml version="1.0" encoding="UTF-8"?>
+<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope" xmlns:tds="http://www.onvif.org/ver10/device/wsdl">
+<env:Body><tds:GetServiceCapabilitiesResponse><tds:Capabilities>
+<tds:Network IPFilter="true" NTP="0" HostnameFromDHCP="false"></tds:Network>
+<tds:Security RemoteUserHandling="false"/>
+<tds:System SystemBackup="false" SystemLogging="true"/>
+</tds:Capabilities></tds:GetServiceCapabilitiesResponse></env:Body></env:Envelope>`,
+			expected: map[string]string{
+				"Network.IPFilter":            "true",
+				"Network.NTP":                 "0",
+				"Network.HostnameFromDHCP":    "false",
+				"Security.RemoteUserHandling": "false",
+				"System.SystemBackup":         "false",
+				"System.SystemLogging":        "true",
+			},
+		},
+		{
+			name: "media capabilities",
+			data: `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope" xmlns:trt="http://www.onvif.org/ver10/media/wsdl">
+<env:Body><trt:GetServiceCapabilitiesResponse><trt:Capabilities SnapshotUri="true" OSD="false">
+<trt:ProfileCapabilities MaximumNumberOfProfiles="4"/>
+<trt:StreamingCapabilities RTPMulticast="false" RTP_TCP="true"/>
+</trt:Capabilities></trt:GetServiceCapabilitiesResponse></env:Body></env:Envelope>`,
+			expected: map[string]string{
+				"SnapshotUri": "true",
+				"OSD":         "false",
+				"ProfileCapabilities.MaximumNumberOfProfiles": "4",
+				"StreamingCapabilities.RTPMulticast":          "false",
+				"StreamingCapabilities.RTP_TCP":               "true",
+			},
+		},
+		{
+			name:          "no capabilities",
+			data:          `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"><env:Body><env:Fault/></env:Body></env:Envelope>`,
+			errorExpected: true,
+		},
+		{
+			name:          "invalid xml",
+			data:          `<env:Envelope><env:Body>`,
+			errorExpected: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			capabilities, err := parseServiceCapabilities([]byte(test.data))
+			if test.errorExpected {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, capabilities)
+		})
+	}
+}
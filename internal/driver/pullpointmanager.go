@@ -85,8 +85,14 @@ func (manager *PullPointManager) newSubscriberOnvifDevice(device OnvifDevice, me
 	}
 	timeout = timeout + time.Duration(httpRequestTimeout)*time.Second
 	params := device.GetDeviceParams()
+	// keep the transport of the device, which addresses its device service and verifies its certificate
+	var transport http.RoundTripper
+	if params.HttpClient != nil {
+		transport = params.HttpClient.Transport
+	}
 	params.HttpClient = &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: transport,
 	}
 	return onvif.NewDevice(params)
 }
@@ -0,0 +1,212 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"bufio"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/spf13/cast"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	// neighborTablePath is the location of the kernel's IPv4 neighbor (ARP) table
+	neighborTablePath = "/proc/net/arp"
+)
+
+const (
+	// neighborFlagsIncomplete is the ARP flags value of an entry which has not been resolved
+	neighborFlagsIncomplete = "0x0"
+	// relocationProbeCacheDuration is the amount of time the results of a multicast probe are reused for relocating
+	// other devices, so that all the devices found Unreachable in a single status check share one probe
+	relocationProbeCacheDuration = 30 * time.Second
+)
+
+// relocationProbeCache holds the result of the most recent multicast probe used for relocating devices
+type relocationProbeCache struct {
+	// mu only guards devices and probedAt, and is not held while probing
+	mu       sync.Mutex
+	devices  []probedDevice
+	probedAt time.Time
+	// probes ensures that concurrent callers share a single multicast probe
+	probes singleflight.Group
+}
+
+// relocateDevice attempts to find an Unreachable device which may have changed its IP address, for example
+// because of a new DHCP lease. The device is looked up by its MAC address in the neighbor table, and by its
+// EndpointRefAddress via a multicast probe. If it is reachable at a new address, the updated protocol properties
+// are returned, otherwise nil is returned. Besides the Address and Port, the properties include the XAddrs, Scheme
+// and DeviceServicePath which locate the device service at the new address, where a nil value removes the property.
+func (d *Driver) relocateDevice(device models.Device) map[string]any {
+	d.configMu.RLock()
+	enabled := d.config.AppCustom.EnableDeviceRelocation
+	multicastEnabled := d.config.AppCustom.DiscoveryMode.IsMulticastEnabled()
+	discoveryEthernetInterface := d.config.AppCustom.DiscoveryEthernetInterface
+	d.configMu.RUnlock()
+
+	if !enabled {
+		return nil
+	}
+
+	currentAddr := cast.ToString(device.Protocols[OnvifProtocol][Address])
+	currentPort := cast.ToString(device.Protocols[OnvifProtocol][Port])
+
+	if mac, err := SanitizeMACAddress(cast.ToString(device.Protocols[OnvifProtocol][MACAddress])); err == nil {
+		address, err := lookupNeighborIP(neighborTablePath, mac)
+		if err != nil {
+			d.lc.Debugf("Unable to read the neighbor table while relocating device %s: %s", device.Name, err.Error())
+		} else if address != "" && address != currentAddr && d.isReachableAt(device, address, currentPort) {
+			d.lc.Infof("Device %s with MAC address %s has been relocated via the neighbor table from %s to %s",
+				device.Name, mac, currentAddr, address)
+			relocation := map[string]any{Address: address, Port: currentPort}
+			if xAddrs := cast.ToString(device.Protocols[OnvifProtocol][XAddrs]); xAddrs != "" {
+				relocation[XAddrs] = relocateXAddrs(xAddrs, currentAddr, address)
+			}
+			return relocation
+		}
+	}
+
+	endpointRef := cast.ToString(device.Protocols[OnvifProtocol][EndpointRefAddress])
+	if !multicastEnabled || endpointRef == "" {
+		return nil
+	}
+	for _, probed := range d.relocationProbe(discoveryEthernetInterface) {
+		if probed.device.GetDeviceParams().EndpointRefAddress != endpointRef {
+			continue
+		}
+		address, port := addressAndPort(probed.device.GetDeviceParams().Xaddr)
+		if (address == currentAddr && port == currentPort) || !d.isReachableAt(device, address, port) {
+			return nil
+		}
+		d.lc.Infof("Device %s with EndpointRefAddress %s has been relocated via multicast from %s:%s to %s:%s",
+			device.Name, endpointRef, currentAddr, currentPort, address, port)
+		return relocationProperties(address, port, probed.location)
+	}
+	return nil
+}
+
+// isReachableAt returns true if the device accepts tcp connections at the specified address and port
+func (d *Driver) isReachableAt(device models.Device, address string, port string) bool {
+	return d.tcpProbe(models.Device{
+		Name: device.Name,
+		Protocols: map[string]models.ProtocolProperties{
+			OnvifProtocol: {Address: address, Port: port},
+		},
+	})
+}
+
+// relocationProperties returns the protocol properties of a device relocated to the address and port, where the device
+// service is at the location advertised by the multicast probe. The properties which discovery leaves out for the
+// default location are removed.
+func relocationProperties(address string, port string, location deviceServiceLocation) map[string]any {
+	relocation := map[string]any{Address: address, Port: port, XAddrs: nil, Scheme: nil, DeviceServicePath: nil}
+	if len(location.XAddrs) > 0 {
+		relocation[XAddrs] = strings.Join(location.XAddrs, " ")
+	}
+	if location.Scheme != "" && location.Scheme != httpScheme {
+		relocation[Scheme] = location.Scheme
+	}
+	if location.Path != "" && location.Path != deviceServicePath {
+		relocation[DeviceServicePath] = location.Path
+	}
+	return relocation
+}
+
+// relocateXAddrs replaces the oldAddress host of the space separated device service addresses with newAddress
+func relocateXAddrs(xAddrs string, oldAddress string, newAddress string) string {
+	fields := strings.Fields(xAddrs)
+	for i, field := range fields {
+		u, err := url.Parse(field)
+		if err != nil || u.Hostname() != oldAddress {
+			continue
+		}
+		if port := u.Port(); port != "" {
+			u.Host = net.JoinHostPort(newAddress, port)
+		} else {
+			u.Host = newAddress
+		}
+		fields[i] = u.String()
+	}
+	return strings.Join(fields, " ")
+}
+
+// relocationProbe returns the devices which respond to a multicast probe on the discovery interfaces. The
+// results are cached for a short time, and concurrent callers share a single probe, without holding the cache lock
+// while probing, so that the other status check workers are not blocked.
+func (d *Driver) relocationProbe(discoveryEthernetInterface string) []probedDevice {
+	d.relocationCache.mu.Lock()
+	if time.Since(d.relocationCache.probedAt) < relocationProbeCacheDuration {
+		devices := d.relocationCache.devices
+		d.relocationCache.mu.Unlock()
+		return devices
+	}
+	d.relocationCache.mu.Unlock()
+
+	result, _, _ := d.relocationCache.probes.Do(discoveryEthernetInterface, func() (any, error) {
+		devices := d.sendRelocationProbe(discoveryEthernetInterface)
+
+		d.relocationCache.mu.Lock()
+		d.relocationCache.devices = devices
+		d.relocationCache.probedAt = time.Now()
+		d.relocationCache.mu.Unlock()
+		return devices, nil
+	})
+	devices, _ := result.([]probedDevice)
+	return devices
+}
+
+// sendRelocationProbe sends a multicast probe on each of the discovery interfaces, and returns the devices which responded
+func (d *Driver) sendRelocationProbe(discoveryEthernetInterface string) []probedDevice {
+	ifaceNames, edgexErr := d.multicastInterfaceNames(discoveryEthernetInterface)
+	if edgexErr != nil {
+		d.lc.Warnf("Unable to send a multicast probe to relocate devices: %s", edgexErr.Error())
+		return nil
+	}
+
+	var devices []probedDevice
+	for _, ifaceName := range ifaceNames {
+		ifaceDevices, err := d.sendMulticastProbe(ifaceName)
+		if err != nil {
+			d.lc.Warnf("Unable to send a multicast probe to relocate devices on interface %s: %s", ifaceName, err.Error())
+			continue
+		}
+		devices = append(devices, ifaceDevices...)
+	}
+	return devices
+}
+
+// lookupNeighborIP returns the IPv4 address associated with the sanitized mac address in the neighbor table
+// at path, or an empty string if the mac address is not in the table.
+func lookupNeighborIP(path string, mac string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // skip the header line
+	for scanner.Scan() {
+		// IP address, HW type, Flags, HW address, Mask, Device
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[2] == neighborFlagsIncomplete {
+			continue
+		}
+		if entryMAC, err := SanitizeMACAddress(fields[3]); err == nil && entryMAC == mac {
+			return fields[0], nil
+		}
+	}
+	return "", scanner.Err()
+}
@@ -0,0 +1,141 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNeighborTable = `IP address       HW type     Flags       HW address            Mask     Device
+192.168.1.1      0x1         0x2         00:11:22:33:44:55     *        eth0
+192.168.1.50     0x1         0x0         aa:bb:cc:dd:ee:ff     *        eth0
+127.0.0.1        0x1         0x2         AA:BB:CC:DD:EE:FF     *        eth1
+`
+
+func writeTestNeighborTable(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "arp")
+	require.NoError(t, os.WriteFile(path, []byte(testNeighborTable), 0600))
+	return path
+}
+
+func TestLookupNeighborIP(t *testing.T) {
+	path := writeTestNeighborTable(t)
+
+	tests := []struct {
+		name     string
+		mac      string
+		expected string
+	}{
+		{name: "found", mac: "00:11:22:33:44:55", expected: "192.168.1.1"},
+		{name: "incomplete entries are skipped", mac: "aa:bb:cc:dd:ee:ff", expected: "127.0.0.1"},
+		{name: "not found", mac: "01:02:03:04:05:06", expected: ""},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			ip, err := lookupNeighborIP(path, test.mac)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, ip)
+		})
+	}
+
+	_, err := lookupNeighborIP(filepath.Join(t.TempDir(), "missing"), "00:11:22:33:44:55")
+	assert.Error(t, err)
+}
+
+func TestDriver_RelocateDevice(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	originalPath := neighborTablePath
+	neighborTablePath = writeTestNeighborTable(t)
+	defer func() { neighborTablePath = originalPath }()
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		mac      string
+		expected map[string]any
+	}{
+		{name: "relocated via the neighbor table", enabled: true, mac: "aa-bb-cc-dd-ee-ff", expected: map[string]any{Address: "127.0.0.1", Port: port,
+			XAddrs: "http://127.0.0.1:" + port + "/onvif/device_service http://[fe80::1]/onvif/device_service"}},
+		{name: "mac address not in the neighbor table", enabled: true, mac: "01:02:03:04:05:06"},
+		{name: "relocation disabled", enabled: false, mac: "aa-bb-cc-dd-ee-ff"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			driver, _ := createDriverWithMockService()
+			driver.config = &ServiceConfig{
+				AppCustom: CustomConfig{
+					EnableDeviceRelocation: test.enabled,
+					DiscoveryMode:          ModeNetScan,
+					ProbeTimeoutMillis:     1000,
+				},
+			}
+			device := models.Device{Name: testDeviceName, Protocols: map[string]models.ProtocolProperties{
+				OnvifProtocol: {
+					Address:    "192.0.2.1",
+					Port:       port,
+					MACAddress: test.mac,
+					XAddrs:     "http://192.0.2.1:" + port + "/onvif/device_service http://[fe80::1]/onvif/device_service",
+				},
+			}}
+
+			assert.Equal(t, test.expected, driver.relocateDevice(device))
+		})
+	}
+}
+
+func TestRelocationProperties(t *testing.T) {
+	tests := []struct {
+		name     string
+		location deviceServiceLocation
+		expected map[string]any
+	}{
+		{name: "default location", location: deviceServiceLocation{XAddrs: []string{"http://192.0.2.2/onvif/device_service"},
+			Scheme: httpScheme, Path: deviceServicePath},
+			expected: map[string]any{Address: "192.0.2.2", Port: "80", XAddrs: "http://192.0.2.2/onvif/device_service",
+				Scheme: nil, DeviceServicePath: nil}},
+		{name: "https location", location: deviceServiceLocation{XAddrs: []string{"https://192.0.2.2/onvif/device"},
+			Scheme: httpsScheme, Path: "/onvif/device"},
+			expected: map[string]any{Address: "192.0.2.2", Port: "80", XAddrs: "https://192.0.2.2/onvif/device",
+				Scheme: httpsScheme, DeviceServicePath: "/onvif/device"}},
+		{name: "no advertised location", expected: map[string]any{Address: "192.0.2.2", Port: "80", XAddrs: nil,
+			Scheme: nil, DeviceServicePath: nil}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, relocationProperties("192.0.2.2", "80", test.location))
+		})
+	}
+}
+
+func TestDriver_relocationProbe_cached(t *testing.T) {
+	driver, _ := createDriverWithMockService()
+	cached := []probedDevice{{scopes: []string{"onvif://www.onvif.org/name/cached"}}}
+	driver.relocationCache.devices = cached
+	driver.relocationCache.probedAt = time.Now()
+
+	// the cached result is returned without sending a probe on the missing interface
+	assert.Equal(t, cached, driver.relocationProbe("missing0"))
+}
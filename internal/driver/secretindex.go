@@ -0,0 +1,133 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+)
+
+// defaultSecretRefreshWorkers is the maximum number of onvif clients rebuilt simultaneously after a secret update, when
+// SecretRefreshWorkers is not set
+const defaultSecretRefreshWorkers = 8
+
+// secretIndex is the reverse index of the secret names which the onvif client of each device depends on, so that only
+// the affected clients are rebuilt when a secret is updated
+type secretIndex struct {
+	mu sync.RWMutex
+	// secrets maps each device name to the secret names its onvif client depends on
+	secrets map[string][]string
+	// devices maps each secret name to the names of the devices which depend on it
+	devices map[string]map[string]struct{}
+}
+
+// set records the secret names which the device depends on, replacing the previous ones
+func (i *secretIndex) set(deviceName string, secretNames []string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.secrets == nil {
+		i.secrets = make(map[string][]string)
+		i.devices = make(map[string]map[string]struct{})
+	}
+	i.removeLocked(deviceName)
+	i.secrets[deviceName] = secretNames
+	for _, secretName := range secretNames {
+		if i.devices[secretName] == nil {
+			i.devices[secretName] = make(map[string]struct{})
+		}
+		i.devices[secretName][deviceName] = struct{}{}
+	}
+}
+
+// forget removes the device from the index
+func (i *secretIndex) forget(deviceName string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.removeLocked(deviceName)
+}
+
+func (i *secretIndex) removeLocked(deviceName string) {
+	for _, secretName := range i.secrets[deviceName] {
+		delete(i.devices[secretName], deviceName)
+		if len(i.devices[secretName]) == 0 {
+			delete(i.devices, secretName)
+		}
+	}
+	delete(i.secrets, deviceName)
+}
+
+// devicesUsing returns the sorted names of the devices which depend on the secret name
+func (i *secretIndex) devicesUsing(secretName string) []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	deviceNames := make([]string, 0, len(i.devices[secretName]))
+	for deviceName := range i.devices[secretName] {
+		deviceNames = append(deviceNames, deviceName)
+	}
+	slices.Sort(deviceNames)
+	return deviceNames
+}
+
+// secretNamesForDevice returns the secret names which the onvif client of the device depends on, which are the secret
+// name of its credentials, and the TLSCASecretName if the device is reached using https
+func (d *Driver) secretNamesForDevice(device models.Device) []string {
+	secretNames := []string{d.secretNameForDevice(device)}
+
+	d.configMu.RLock()
+	caSecretName := d.config.AppCustom.TLSCASecretName
+	d.configMu.RUnlock()
+	if scheme, _ := locationFromProtocols(device.Protocols); scheme == httpsScheme && caSecretName != "" && caSecretName != secretNames[0] {
+		secretNames = append(secretNames, caSecretName)
+	}
+	return secretNames
+}
+
+// indexDeviceSecrets records the secret names which the onvif clients of the devices depend on
+func (d *Driver) indexDeviceSecrets(devices ...models.Device) {
+	for _, device := range devices {
+		if _, ok := device.Protocols[OnvifProtocol]; !ok {
+			continue
+		}
+		d.secretIndex.set(device.Name, d.secretNamesForDevice(device))
+	}
+}
+
+// refreshOnvifClients rebuilds the onvif clients of the devices if their parameters changed, which also checks their
+// status, using at most SecretRefreshWorkers simultaneous workers
+func (d *Driver) refreshOnvifClients(devices []models.Device) {
+	d.configMu.RLock()
+	workers := d.config.AppCustom.SecretRefreshWorkers
+	d.configMu.RUnlock()
+	if workers <= 0 {
+		workers = defaultSecretRefreshWorkers
+	}
+	workers = min(workers, len(devices))
+
+	queue := make(chan models.Device)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for device := range queue {
+				d.lc.Tracef("Updating onvif client for device %s", device.Name)
+				if err := d.updateOnvifClient(device); err != nil {
+					d.lc.Errorf("Unable to update onvif client for device: %s, %v", device.Name, err)
+				}
+			}
+		}()
+	}
+	for _, device := range devices {
+		queue <- device
+	}
+	close(queue)
+	wg.Wait()
+}
@@ -0,0 +1,100 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSecretIndex(t *testing.T) {
+	index := secretIndex{}
+	assert.Empty(t, index.devicesUsing("secret1"))
+
+	index.set("camera2", []string{"secret1", "ca"})
+	index.set("camera1", []string{"secret1"})
+	assert.Equal(t, []string{"camera1", "camera2"}, index.devicesUsing("secret1"))
+	assert.Equal(t, []string{"camera2"}, index.devicesUsing("ca"))
+
+	index.set("camera1", []string{"secret2"})
+	assert.Equal(t, []string{"camera2"}, index.devicesUsing("secret1"))
+	assert.Equal(t, []string{"camera1"}, index.devicesUsing("secret2"))
+
+	index.forget("camera2")
+	assert.Empty(t, index.devicesUsing("secret1"))
+	assert.Empty(t, index.devicesUsing("ca"))
+	assert.NotContains(t, index.devices, "ca")
+}
+
+func TestDriver_secretNamesForDevice(t *testing.T) {
+	driver, mockService := createDriverWithMockService()
+	driver.config.AppCustom.DefaultSecretName = defaultSecretName
+	driver.config.AppCustom.TLSCASecretName = "ca"
+	driver.macAddressMapper = NewMACAddressMapper(mockService)
+	driver.macAddressMapper.credsMap = convertMACMappings(t, map[string]string{secret1Name: testMACAddress})
+
+	tests := []struct {
+		name     string
+		protocol models.ProtocolProperties
+		expected []string
+	}{
+		{name: "default", protocol: models.ProtocolProperties{}, expected: []string{defaultSecretName}},
+		{name: "MAC address mapping", protocol: models.ProtocolProperties{MACAddress: testMACAddress}, expected: []string{secret1Name}},
+		{name: "secret name reference", protocol: models.ProtocolProperties{SecretName: "direct", MACAddress: testMACAddress},
+			expected: []string{"direct"}},
+		{name: "https", protocol: models.ProtocolProperties{Scheme: httpsScheme}, expected: []string{defaultSecretName, "ca"}},
+		{name: "https sharing the CA secret", protocol: models.ProtocolProperties{Scheme: httpsScheme, SecretName: "ca"},
+			expected: []string{"ca"}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			device := createTestDeviceWithProtocols(map[string]models.ProtocolProperties{OnvifProtocol: test.protocol})
+			assert.Equal(t, test.expected, driver.secretNamesForDevice(device))
+		})
+	}
+}
+
+func TestDriver_secretUpdated(t *testing.T) {
+	driver, mockService := createDriverWithMockService()
+	driver.config.AppCustom.DefaultSecretName = defaultSecretName
+	driver.config.AppCustom.SecretRefreshWorkers = 2
+	driver.macAddressMapper = NewMACAddressMapper(mockService)
+	driver.macAddressMapper.credsMap = convertMACMappings(t, map[string]string{secret1Name: testMACAddress})
+
+	var devices []models.Device
+	for _, device := range []models.Device{
+		{Name: "mapped1", Protocols: map[string]models.ProtocolProperties{OnvifProtocol: {MACAddress: testMACAddress}}},
+		{Name: "mapped2", Protocols: map[string]models.ProtocolProperties{OnvifProtocol: {MACAddress: testMACAddress}}},
+		{Name: "referenced", Protocols: map[string]models.ProtocolProperties{OnvifProtocol: {SecretName: secret1Name}}},
+		{Name: "removed", Protocols: map[string]models.ProtocolProperties{OnvifProtocol: {MACAddress: testMACAddress}}},
+		{Name: "default", Protocols: map[string]models.ProtocolProperties{OnvifProtocol: {}}},
+		{Name: "other protocol", Protocols: map[string]models.ProtocolProperties{"other": {}}},
+	} {
+		devices = append(devices, device)
+		mockService.On("GetDeviceByName", device.Name).Return(device, nil).Maybe()
+	}
+	driver.indexDeviceSecrets(devices...)
+	driver.secretIndex.forget("removed")
+	mockService.On("GetDeviceByName", "missing").Return(models.Device{}, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "not found", nil))
+	driver.secretIndex.set("missing", []string{secret1Name})
+
+	driver.secretUpdated(secret1Name)
+
+	for _, name := range []string{"mapped1", "mapped2", "referenced", "missing"} {
+		mockService.AssertCalled(t, "GetDeviceByName", name)
+	}
+	for _, name := range []string{"removed", "default", "other protocol"} {
+		mockService.AssertNotCalled(t, "GetDeviceByName", name)
+	}
+	mockService.AssertNotCalled(t, "Devices", mock.Anything)
+}
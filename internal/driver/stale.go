@@ -0,0 +1,190 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/spf13/cast"
+)
+
+// staleDeviceTracker keeps track of how many consecutive status checks and discoveries have missed each device
+type staleDeviceTracker struct {
+	mu      sync.Mutex
+	devices map[string]*deviceMisses
+}
+
+// deviceMisses holds the consecutive misses of a device since it was last seen
+type deviceMisses struct {
+	statusChecks int
+	discoveries  int
+	// seenAt is the time the device was last seen by a status check or discovery
+	seenAt time.Time
+}
+
+func (t *staleDeviceTracker) get(deviceName string) *deviceMisses {
+	if t.devices == nil {
+		t.devices = make(map[string]*deviceMisses)
+	}
+	misses, found := t.devices[deviceName]
+	if !found {
+		misses = &deviceMisses{}
+		t.devices[deviceName] = misses
+	}
+	return misses
+}
+
+// seen resets the misses of the device
+func (t *staleDeviceTracker) seen(deviceName string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	*t.get(deviceName) = deviceMisses{seenAt: now}
+}
+
+// missedStatusCheck counts a status check which found the device Unreachable, and returns the misses of the device
+func (t *staleDeviceTracker) missedStatusCheck(deviceName string) deviceMisses {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	misses := t.get(deviceName)
+	misses.statusChecks++
+	return *misses
+}
+
+// missedDiscovery counts a discovery which started at the specified time without finding the device, and returns the
+// misses of the device. Returns false if the device was seen since the discovery started.
+func (t *staleDeviceTracker) missedDiscovery(deviceName string, started time.Time) (deviceMisses, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	misses := t.get(deviceName)
+	if !misses.seenAt.Before(started) {
+		return *misses, false
+	}
+	misses.discoveries++
+	return *misses, true
+}
+
+// forget removes the misses of a device which no longer exists
+func (t *staleDeviceTracker) forget(deviceName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.devices, deviceName)
+}
+
+// staleDevicePolicy returns the stale device policy of the device. The policy named by the StaleDevicePolicy property
+// of the device is used first, followed by the policy named by any of its labels, and otherwise the default policy.
+func (d *Driver) staleDevicePolicy(device models.Device) StaleDevicePolicy {
+	d.configMu.RLock()
+	defer d.configMu.RUnlock()
+
+	if name := cast.ToString(device.Properties[StaleDevicePolicyProperty]); name != "" {
+		if policy, found := d.config.AppCustom.StaleDevicePolicies[name]; found {
+			return policy
+		}
+		d.lc.Warnf("Device %s refers to the stale device policy %s which does not exist", device.Name, name)
+	}
+	for _, label := range device.Labels {
+		if policy, found := d.config.AppCustom.StaleDevicePolicies[label]; found {
+			return policy
+		}
+	}
+	return d.config.AppCustom.StaleDevicePolicy
+}
+
+// deviceSeen resets the misses of a device which was found by a status check. If the device was set to Down or labeled
+// stale, it is set back to Up and the stale label is removed.
+func (d *Driver) deviceSeen(device models.Device) {
+	d.staleDevices.seen(device.Name, time.Now())
+
+	if device.OperatingState != models.Down && !slices.Contains(device.Labels, StaleLabel) {
+		return
+	}
+	d.lc.Infof("Device %s has been seen again, setting it to %s", device.Name, models.Up)
+	operatingState := string(models.Up)
+	labels := slices.DeleteFunc(slices.Clone(device.Labels), func(label string) bool { return label == StaleLabel })
+	if err := d.sdkService.PatchDevice(dtos.UpdateDevice{
+		Name:           &device.Name,
+		OperatingState: &operatingState,
+		Labels:         labels,
+	}); err != nil {
+		d.lc.Errorf("Failed to restore the device %s: %s", device.Name, err.Error())
+	}
+}
+
+// deviceMissedStatusCheck counts a status check which found the device Unreachable, and applies its stale device policy
+func (d *Driver) deviceMissedStatusCheck(device models.Device) {
+	d.applyStaleDevicePolicy(device, d.staleDevices.missedStatusCheck(device.Name))
+}
+
+// recordMissedDiscoveries counts a discovery which started at the specified time against every onvif device which was
+// not seen since, and applies their stale device policy. Devices which are UpWithAuth are not counted when they were
+// skipped by netscan discovery.
+func (d *Driver) recordMissedDiscoveries(started time.Time, skipUpWithAuth bool) {
+	for _, device := range d.sdkService.Devices() {
+		protocol, ok := device.Protocols[OnvifProtocol]
+		if !ok {
+			continue
+		}
+		if skipUpWithAuth && cast.ToString(protocol[DeviceStatus]) == UpWithAuth {
+			continue
+		}
+		if !d.deviceLastSeen(device).Before(started) {
+			continue
+		}
+		if misses, missed := d.staleDevices.missedDiscovery(device.Name, started); missed {
+			d.lc.Debugf("Device %s was not found by %d consecutive discoveries", device.Name, misses.discoveries)
+			d.applyStaleDevicePolicy(device, misses)
+		}
+	}
+}
+
+// applyStaleDevicePolicy sets the device to Down, labels it stale, or removes it, once its misses reach the thresholds
+// of its stale device policy
+func (d *Driver) applyStaleDevicePolicy(device models.Device, misses deviceMisses) {
+	policy := d.staleDevicePolicy(device)
+
+	if policy.RemoveAfterMissedDiscoveries > 0 && misses.discoveries >= policy.RemoveAfterMissedDiscoveries {
+		d.lc.Infof("Removing the device %s, which was not found by %d consecutive discoveries", device.Name, misses.discoveries)
+		if err := d.sdkService.RemoveDeviceByName(device.Name); err != nil {
+			d.lc.Errorf("Failed to remove the stale device %s: %s", device.Name, err.Error())
+			return
+		}
+		d.staleDevices.forget(device.Name)
+		return
+	}
+
+	shouldUpdate := false
+	update := dtos.UpdateDevice{Name: &device.Name}
+	if policy.DownAfterMissedStatusChecks > 0 && misses.statusChecks >= policy.DownAfterMissedStatusChecks &&
+		device.OperatingState != models.Down {
+		d.lc.Infof("Setting the device %s to %s, after %d consecutive %s status checks", device.Name, models.Down,
+			misses.statusChecks, Unreachable)
+		operatingState := string(models.Down)
+		update.OperatingState = &operatingState
+		shouldUpdate = true
+	}
+	if policy.StaleAfterMissedDiscoveries > 0 && misses.discoveries >= policy.StaleAfterMissedDiscoveries &&
+		!slices.Contains(device.Labels, StaleLabel) {
+		d.lc.Infof("Labeling the device %s as %s, after %d consecutive discoveries", device.Name, StaleLabel, misses.discoveries)
+		update.Labels = mergeLabels(slices.Clone(device.Labels), StaleLabel)
+		shouldUpdate = true
+	}
+
+	if shouldUpdate {
+		if err := d.sdkService.PatchDevice(update); err != nil {
+			d.lc.Errorf("Failed to update the stale device %s: %s", device.Name, err.Error())
+		}
+	}
+}
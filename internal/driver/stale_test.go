@@ -0,0 +1,174 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaleDeviceTracker(t *testing.T) {
+	tracker := staleDeviceTracker{}
+	start := time.Now()
+
+	assert.Equal(t, 1, tracker.missedStatusCheck("camera1").statusChecks)
+	assert.Equal(t, 2, tracker.missedStatusCheck("camera1").statusChecks)
+	misses, missed := tracker.missedDiscovery("camera1", start)
+	assert.True(t, missed)
+	assert.Equal(t, deviceMisses{statusChecks: 2, discoveries: 1}, misses)
+
+	// a device seen since the discovery started is not missed
+	tracker.seen("camera1", start.Add(time.Second))
+	misses, missed = tracker.missedDiscovery("camera1", start)
+	assert.False(t, missed)
+	assert.Zero(t, misses.statusChecks)
+	assert.Zero(t, misses.discoveries)
+
+	misses, missed = tracker.missedDiscovery("camera1", start.Add(time.Minute))
+	assert.True(t, missed)
+	assert.Equal(t, 1, misses.discoveries)
+
+	tracker.forget("camera1")
+	assert.Equal(t, 1, tracker.missedStatusCheck("camera1").statusChecks)
+}
+
+func TestDriver_staleDevicePolicy(t *testing.T) {
+	defaultPolicy := StaleDevicePolicy{DownAfterMissedStatusChecks: 10}
+	labelPolicy := StaleDevicePolicy{StaleAfterMissedDiscoveries: 1}
+	watcherPolicy := StaleDevicePolicy{RemoveAfterMissedDiscoveries: 2}
+
+	driver, _ := createDriverWithMockService()
+	driver.config.AppCustom.StaleDevicePolicy = defaultPolicy
+	driver.config.AppCustom.StaleDevicePolicies = map[string]StaleDevicePolicy{
+		"temporary": labelPolicy,
+		"watcher":   watcherPolicy,
+	}
+
+	tests := []struct {
+		name     string
+		device   models.Device
+		expected StaleDevicePolicy
+	}{
+		{
+			name:     "default",
+			device:   models.Device{Name: "camera", Labels: []string{"auto-discovery"}},
+			expected: defaultPolicy,
+		},
+		{
+			name:     "label",
+			device:   models.Device{Name: "camera", Labels: []string{"auto-discovery", "temporary"}},
+			expected: labelPolicy,
+		},
+		{
+			name: "provision watcher property",
+			device: models.Device{Name: "camera", Labels: []string{"temporary"},
+				Properties: map[string]any{StaleDevicePolicyProperty: "watcher"}},
+			expected: watcherPolicy,
+		},
+		{
+			name: "unknown provision watcher property",
+			device: models.Device{Name: "camera", Labels: []string{"temporary"},
+				Properties: map[string]any{StaleDevicePolicyProperty: "unknown"}},
+			expected: labelPolicy,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, driver.staleDevicePolicy(test.device))
+		})
+	}
+}
+
+func TestDriver_applyStaleDevicePolicy(t *testing.T) {
+	policy := StaleDevicePolicy{
+		DownAfterMissedStatusChecks:  3,
+		StaleAfterMissedDiscoveries:  1,
+		RemoveAfterMissedDiscoveries: 2,
+	}
+	down := string(models.Down)
+
+	tests := []struct {
+		name           string
+		device         models.Device
+		misses         deviceMisses
+		expectedUpdate *dtos.UpdateDevice
+		expectRemove   bool
+	}{
+		{
+			name:   "below the thresholds",
+			device: models.Device{Name: testDeviceName, OperatingState: models.Up},
+			misses: deviceMisses{statusChecks: 2},
+		},
+		{
+			name:           "down",
+			device:         models.Device{Name: testDeviceName, OperatingState: models.Up},
+			misses:         deviceMisses{statusChecks: 3},
+			expectedUpdate: &dtos.UpdateDevice{OperatingState: &down},
+		},
+		{
+			name:   "already down",
+			device: models.Device{Name: testDeviceName, OperatingState: models.Down},
+			misses: deviceMisses{statusChecks: 4},
+		},
+		{
+			name:           "stale",
+			device:         models.Device{Name: testDeviceName, OperatingState: models.Down, Labels: []string{"auto-discovery"}},
+			misses:         deviceMisses{statusChecks: 4, discoveries: 1},
+			expectedUpdate: &dtos.UpdateDevice{Labels: []string{"auto-discovery", StaleLabel}},
+		},
+		{
+			name:         "removed",
+			device:       models.Device{Name: testDeviceName, OperatingState: models.Down, Labels: []string{StaleLabel}},
+			misses:       deviceMisses{statusChecks: 5, discoveries: 2},
+			expectRemove: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			driver, mockService := createDriverWithMockService()
+			driver.config.AppCustom.StaleDevicePolicy = policy
+			if test.expectedUpdate != nil {
+				test.expectedUpdate.Name = &test.device.Name
+				mockService.On("PatchDevice", *test.expectedUpdate).Return(nil).Once()
+			}
+			if test.expectRemove {
+				mockService.On("RemoveDeviceByName", test.device.Name).Return(nil).Once()
+			}
+
+			driver.applyStaleDevicePolicy(test.device, test.misses)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDriver_deviceSeen(t *testing.T) {
+	driver, mockService := createDriverWithMockService()
+	device := models.Device{Name: testDeviceName, OperatingState: models.Down, Labels: []string{"auto-discovery", StaleLabel}}
+	up := string(models.Up)
+	mockService.On("PatchDevice", dtos.UpdateDevice{
+		Name:           &device.Name,
+		OperatingState: &up,
+		Labels:         []string{"auto-discovery"},
+	}).Return(nil).Once()
+
+	driver.staleDevices.missedStatusCheck(device.Name)
+	driver.deviceSeen(device)
+	assert.Equal(t, 1, driver.staleDevices.missedStatusCheck(device.Name).statusChecks)
+	assert.Equal(t, []string{"auto-discovery", StaleLabel}, device.Labels)
+
+	// nothing to restore
+	driver.deviceSeen(models.Device{Name: testDeviceName, OperatingState: models.Up})
+	mockService.AssertExpectations(t)
+}
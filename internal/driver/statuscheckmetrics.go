@@ -0,0 +1,98 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/v4/pkg/interfaces"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	StatusCheckRestPath = "statuscheck"
+	apiStatusCheckRoute = common.ApiBase + "/" + StatusCheckRestPath
+)
+
+// StatusCheckRound holds the metrics of a single round of status checks
+type StatusCheckRound struct {
+	StartedAt time.Time
+	Duration  time.Duration
+	// Devices is the number of devices which were due to be checked
+	Devices int
+	// Checked is the number of devices which were checked
+	Checked int
+	// Skipped is the number of devices which could not be checked before the round timed out
+	Skipped int
+	// Workers is the number of workers used to check the devices
+	Workers int
+}
+
+// StatusCheckMetrics holds the metrics of the status checks since the service started
+type StatusCheckMetrics struct {
+	LastRound    *StatusCheckRound `json:",omitempty"`
+	Rounds       int
+	TotalChecked int
+	TotalSkipped int
+	// SkippedRounds is the number of rounds which skipped at least one device
+	SkippedRounds int
+	MaxDuration   time.Duration
+}
+
+// statusCheckMetrics keeps track of the metrics of the status check rounds
+type statusCheckMetrics struct {
+	mu      sync.Mutex
+	metrics StatusCheckMetrics
+}
+
+// record adds the metrics of a completed round
+func (m *statusCheckMetrics) record(round StatusCheckRound) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.metrics.LastRound = &round
+	m.metrics.Rounds++
+	m.metrics.TotalChecked += round.Checked
+	m.metrics.TotalSkipped += round.Skipped
+	if round.Skipped > 0 {
+		m.metrics.SkippedRounds++
+	}
+	m.metrics.MaxDuration = max(m.metrics.MaxDuration, round.Duration)
+}
+
+// get returns a copy of the current metrics
+func (m *statusCheckMetrics) get() StatusCheckMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	metrics := m.metrics
+	if metrics.LastRound != nil {
+		lastRound := *metrics.LastRound
+		metrics.LastRound = &lastRound
+	}
+	return metrics
+}
+
+// addStatusCheckRoutes adds the route for querying the status check metrics
+func (d *Driver) addStatusCheckRoutes() errors.EdgeX {
+	if err := d.sdkService.AddCustomRoute(apiStatusCheckRoute, interfaces.Authenticated, d.getStatusCheckMetrics, http.MethodGet); err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("unable to add required route: %s: %s", apiStatusCheckRoute, err.Error()), err)
+	}
+	d.lc.Infof("Route %s added.", apiStatusCheckRoute)
+	return nil
+}
+
+// getStatusCheckMetrics returns the metrics of the status check rounds
+func (d *Driver) getStatusCheckMetrics(c echo.Context) error {
+	return c.JSON(http.StatusOK, d.statusCheckMetrics.get())
+}
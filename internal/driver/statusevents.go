@@ -0,0 +1,115 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+)
+
+const (
+	// DeviceStatusChange is the EdgeX function of the device resource which status transitions are published on
+	DeviceStatusChange = "DeviceStatusChange"
+	// StatusChangeSystemEventAction is the action of the system events published for status transitions
+	StatusChangeSystemEventAction = "statuschange"
+
+	// StatusChangeRelocated is the reason of a transition caused by finding the camera at a new address
+	StatusChangeRelocated = "relocated"
+	// StatusChangeRecovered is the reason of a transition to UpWithAuth
+	StatusChangeRecovered = "recovered"
+)
+
+// StatusChange describes a transition of the status of a camera
+type StatusChange struct {
+	DeviceName string
+	OldStatus  string
+	NewStatus  string
+	// Reason is either the kind of error found by the status check, StatusChangeRelocated or StatusChangeRecovered
+	Reason string
+	// Message is the error found by the status check, if any
+	Message string `json:",omitempty"`
+	// Timestamp is the time of the transition in nanoseconds since the epoch
+	Timestamp int64
+}
+
+// statusChangeReason returns the reason of a transition to the status found by the observation
+func statusChangeReason(observation healthObservation, relocated bool) (string, string) {
+	switch {
+	case relocated:
+		return StatusChangeRelocated, ""
+	case observation.status == UpWithAuth || observation.err == nil:
+		return StatusChangeRecovered, ""
+	}
+	return classifyHealthError(observation.err), observation.err.Error()
+}
+
+// getDeviceResourceByGetFunction returns the device resource of the profile of the device which uses the EdgeX getFunction
+func (d *Driver) getDeviceResourceByGetFunction(device models.Device, getFunction string) (r models.DeviceResource, edgexErr errors.EdgeX) {
+	profile, err := d.sdkService.GetProfileByName(device.ProfileName)
+	if err != nil {
+		return r, errors.NewCommonEdgeXWrapper(err)
+	}
+	for _, r := range profile.DeviceResources {
+		val, ok := r.Attributes[GetFunction]
+		if ok && fmt.Sprint(val) == getFunction {
+			return r, nil
+		}
+	}
+	return r, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("device resource with Getfunciton '%s' not found", getFunction), nil)
+}
+
+// publishStatusChange publishes a transition of the status of a camera as an async reading of the device resource using
+// the DeviceStatusChange function, if the profile of the camera has one, and as a system event if EnableStatusChangeSystemEvent is set
+func (d *Driver) publishStatusChange(device models.Device, oldStatus string, newStatus string, observation healthObservation, relocated bool) {
+	change := StatusChange{
+		DeviceName: device.Name,
+		OldStatus:  oldStatus,
+		NewStatus:  newStatus,
+		Timestamp:  time.Now().UnixNano(),
+	}
+	change.Reason, change.Message = statusChangeReason(observation, relocated)
+
+	if resource, edgexErr := d.getDeviceResourceByGetFunction(device, DeviceStatusChange); edgexErr != nil {
+		d.lc.Debugf("Status change of device %s is not published as a reading: %s", device.Name, edgexErr.Error())
+	} else if cv, err := sdkModel.NewCommandValue(resource.Name, common.ValueTypeObject, change); err != nil {
+		d.lc.Warnf("Failed to create the status change reading of device %s: %s", device.Name, err.Error())
+	} else {
+		d.sendStatusChangeReading(&sdkModel.AsyncValues{
+			DeviceName:    device.Name,
+			CommandValues: []*sdkModel.CommandValue{cv},
+		})
+	}
+
+	d.configMu.RLock()
+	publishSystemEvent := d.config.AppCustom.EnableStatusChangeSystemEvent
+	d.configMu.RUnlock()
+	if publishSystemEvent {
+		d.sdkService.PublishGenericSystemEvent(common.DeviceSystemEventType, StatusChangeSystemEventAction, change)
+	}
+}
+
+// sendStatusChangeReading sends the status change reading to the async values channel without blocking the status
+// check. The reading is dropped if the channel is full, or if the driver is stopping and the channel may be closed.
+func (d *Driver) sendStatusChangeReading(asyncValues *sdkModel.AsyncValues) {
+	d.stoppingMu.RLock()
+	defer d.stoppingMu.RUnlock()
+
+	if d.stopping {
+		d.lc.Debugf("Status change reading of device %s is dropped, the driver is stopping", asyncValues.DeviceName)
+		return
+	}
+	select {
+	case d.sdkService.AsyncValuesChannel() <- asyncValues:
+	default:
+		d.lc.Warnf("Status change reading of device %s is dropped, the async values channel is full", asyncValues.DeviceName)
+	}
+}
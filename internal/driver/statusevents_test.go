@@ -0,0 +1,125 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"testing"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusChangeReason(t *testing.T) {
+	authErr := errors.NewCommonEdgeX(errors.KindInvalidId, "failed to verify the authentication", nil)
+
+	tests := []struct {
+		name            string
+		observation     healthObservation
+		relocated       bool
+		expectedReason  string
+		expectedMessage string
+	}{
+		{"relocated", healthObservation{status: Reachable, err: authErr}, true, StatusChangeRelocated, ""},
+		{"recovered", healthObservation{status: UpWithAuth}, false, StatusChangeRecovered, ""},
+		{"auth", healthObservation{status: UpWithoutAuth, err: authErr}, false, HealthErrorAuth, authErr.Error()},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			reason, message := statusChangeReason(test.observation, test.relocated)
+			assert.Equal(t, test.expectedReason, reason)
+			assert.Equal(t, test.expectedMessage, message)
+		})
+	}
+}
+
+func TestDriver_publishStatusChange(t *testing.T) {
+	device := models.Device{Name: testDeviceName, ProfileName: "onvif-camera"}
+	profile := models.DeviceProfile{Name: "onvif-camera", DeviceResources: []models.DeviceResource{
+		{Name: "CameraEvent", Attributes: map[string]any{GetFunction: CameraEvent}},
+		{Name: "StatusChange", Attributes: map[string]any{GetFunction: DeviceStatusChange}},
+	}}
+	observation := healthObservation{status: Unreachable, err: errors.NewCommonEdgeX(errors.KindServerError, "Onvif error: fault", nil)}
+
+	tests := []struct {
+		name              string
+		profile           models.DeviceProfile
+		systemEvent       bool
+		expectReading     bool
+		expectSystemEvent bool
+	}{
+		{name: "reading", profile: profile, expectReading: true},
+		{name: "reading and system event", profile: profile, systemEvent: true, expectReading: true, expectSystemEvent: true},
+		{name: "no resource", profile: models.DeviceProfile{Name: "onvif-camera"}, systemEvent: true, expectSystemEvent: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			driver, mockService := createDriverWithMockService()
+			driver.config.AppCustom.EnableStatusChangeSystemEvent = test.systemEvent
+			asyncValues := make(chan *sdkModel.AsyncValues, 1)
+			mockService.On("GetProfileByName", profile.Name).Return(test.profile, nil)
+			if test.expectReading {
+				mockService.On("AsyncValuesChannel").Return(asyncValues)
+			}
+			if test.expectSystemEvent {
+				mockService.On("PublishGenericSystemEvent", common.DeviceSystemEventType, StatusChangeSystemEventAction,
+					mock.MatchedBy(func(change StatusChange) bool {
+						return change.OldStatus == UpWithAuth && change.NewStatus == Unreachable && change.Reason == HealthErrorSOAPFault
+					})).Once()
+			}
+
+			driver.publishStatusChange(device, UpWithAuth, Unreachable, observation, false)
+			mockService.AssertExpectations(t)
+
+			if !test.expectReading {
+				assert.Empty(t, asyncValues)
+				return
+			}
+			require.Len(t, asyncValues, 1)
+			values := <-asyncValues
+			assert.Equal(t, testDeviceName, values.DeviceName)
+			require.Len(t, values.CommandValues, 1)
+			assert.Equal(t, "StatusChange", values.CommandValues[0].DeviceResourceName)
+			change, ok := values.CommandValues[0].Value.(StatusChange)
+			require.True(t, ok)
+			assert.Equal(t, StatusChange{
+				DeviceName: testDeviceName,
+				OldStatus:  UpWithAuth,
+				NewStatus:  Unreachable,
+				Reason:     HealthErrorSOAPFault,
+				Message:    observation.err.Error(),
+				Timestamp:  change.Timestamp,
+			}, change)
+		})
+	}
+}
+
+func TestDriver_sendStatusChangeReading(t *testing.T) {
+	driver, mockService := createDriverWithMockService()
+	asyncValues := make(chan *sdkModel.AsyncValues, 1)
+	mockService.On("AsyncValuesChannel").Return(asyncValues)
+
+	driver.sendStatusChangeReading(&sdkModel.AsyncValues{DeviceName: "first"})
+	// the channel is full, so the reading is dropped instead of blocking the status check
+	driver.sendStatusChangeReading(&sdkModel.AsyncValues{DeviceName: "second"})
+	require.Len(t, asyncValues, 1)
+	assert.Equal(t, "first", (<-asyncValues).DeviceName)
+
+	// the channel is closed by Stop, so no reading is sent once the driver is stopping
+	require.NoError(t, driver.Stop(false))
+	assert.NotPanics(t, func() { driver.sendStatusChangeReading(&sdkModel.AsyncValues{DeviceName: "third"}) })
+	_, open := <-asyncValues
+	assert.False(t, open)
+}
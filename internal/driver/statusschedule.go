@@ -0,0 +1,196 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/spf13/cast"
+)
+
+// flappingStableChecks is the number of consecutive checks with an unchanged status after which a device whose
+// status changed is no longer considered to be flapping
+const flappingStableChecks = 3
+
+// statusSchedule keeps track of when the status of each device was last checked, and how stable it is
+type statusSchedule struct {
+	mu      sync.Mutex
+	devices map[string]*deviceSchedule
+}
+
+// deviceSchedule holds the status check history of a device
+type deviceSchedule struct {
+	lastCheck time.Time
+	status    string
+	// stableChecks is the number of consecutive checks which found the same status
+	stableChecks int
+	// changed indicates if the status has changed since the device was first checked
+	changed bool
+}
+
+// flapping returns true if the status of the device changed recently
+func (s deviceSchedule) flapping() bool {
+	return s.changed && s.stableChecks < flappingStableChecks
+}
+
+// record stores the status found by a check of the device
+func (s *statusSchedule) record(deviceName string, status string, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.devices == nil {
+		s.devices = make(map[string]*deviceSchedule)
+	}
+	schedule, found := s.devices[deviceName]
+	switch {
+	case !found:
+		s.devices[deviceName] = &deviceSchedule{lastCheck: now, status: status}
+		return
+	case schedule.status != status:
+		schedule.changed = true
+		schedule.stableChecks = 0
+	default:
+		schedule.stableChecks++
+	}
+	schedule.status = status
+	schedule.lastCheck = now
+}
+
+// get returns the status check history of the device, and false if the device has never been checked
+func (s *statusSchedule) get(deviceName string) (deviceSchedule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, found := s.devices[deviceName]
+	if !found {
+		return deviceSchedule{}, false
+	}
+	return *schedule, true
+}
+
+// markAllDue makes the status check of every device due, keeping the status history of the devices
+func (s *statusSchedule) markAllDue() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, schedule := range s.devices {
+		schedule.lastCheck = time.Time{}
+	}
+}
+
+// forget removes the status check history of a device which no longer exists
+func (s *statusSchedule) forget(deviceName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.devices, deviceName)
+}
+
+// statusCheckIntervals holds the status check intervals of the writable configuration
+type statusCheckIntervals struct {
+	normal   time.Duration
+	healthy  time.Duration
+	flapping time.Duration
+	max      time.Duration
+}
+
+// statusCheckConfig returns if status checking is enabled, and the configured status check intervals. Any interval which
+// is not set defaults to the CheckStatusInterval.
+func (d *Driver) statusCheckConfig() (bool, statusCheckIntervals) {
+	d.configMu.RLock()
+	defer d.configMu.RUnlock()
+
+	maxInterval := d.config.AppCustom.MaxCheckStatusInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultMaxStatusInterval
+	}
+	intervals := statusCheckIntervals{
+		normal:   time.Duration(d.config.AppCustom.CheckStatusInterval) * time.Second,
+		healthy:  time.Duration(d.config.AppCustom.HealthyCheckStatusInterval) * time.Second,
+		flapping: time.Duration(d.config.AppCustom.FlappingCheckStatusInterval) * time.Second,
+		max:      time.Duration(maxInterval) * time.Second,
+	}
+	if intervals.healthy <= 0 {
+		intervals.healthy = intervals.normal
+	}
+	if intervals.flapping <= 0 {
+		intervals.flapping = intervals.normal
+	}
+	return d.config.AppCustom.EnableStatusCheck, intervals
+}
+
+// forDevice returns the status check interval of the device. The CheckStatusInterval protocol property of the device
+// takes precedence, otherwise devices whose status changed recently are checked at the flapping interval, and devices
+// which are stable and UpWithAuth at the healthy interval. The interval is limited to between one second and the max.
+func (i statusCheckIntervals) forDevice(device models.Device, schedule deviceSchedule) time.Duration {
+	interval := i.normal
+	if override := cast.ToInt(device.Protocols[OnvifProtocol][CheckStatusInterval]); override > 0 {
+		interval = time.Duration(override) * time.Second
+	} else if schedule.flapping() {
+		interval = i.flapping
+	} else if schedule.status == UpWithAuth {
+		interval = i.healthy
+	}
+	return min(max(interval, time.Second), i.max)
+}
+
+// nextStatusChecks returns the devices whose status check is due, and the duration until the next device is due
+func (d *Driver) nextStatusChecks(intervals statusCheckIntervals, now time.Time) ([]models.Device, time.Duration) {
+	var due []models.Device
+	wait := intervals.max
+	for _, device := range d.sdkService.Devices() {
+		schedule, found := d.statusSchedule.get(device.Name)
+		next := schedule.lastCheck.Add(intervals.forDevice(device, schedule))
+		if !found || !now.Before(next) {
+			due = append(due, device)
+			wait = 0
+			continue
+		}
+		wait = min(wait, next.Sub(now))
+	}
+	return due, wait
+}
+
+// checkDueStatuses checks the status of the devices which are due, and returns the duration until the next device is due.
+// The configuration is read on every call, so that changes to the writable configuration take effect immediately.
+func (d *Driver) checkDueStatuses() time.Duration {
+	enabled, intervals := d.statusCheckConfig()
+	if !enabled {
+		return intervals.max
+	}
+
+	due, _ := d.nextStatusChecks(intervals, time.Now())
+	if len(due) > 0 {
+		// the round must complete before the shortest interval of the devices elapses
+		timeout := intervals.max
+		for _, device := range due {
+			schedule, _ := d.statusSchedule.get(device.Name)
+			timeout = min(timeout, intervals.forDevice(device, schedule))
+		}
+		d.checkStatusOfDevices(due, timeout)
+	}
+
+	_, wait := d.nextStatusChecks(intervals, time.Now())
+	return wait
+}
+
+// warnStatusCheckIntervals logs a warning for every configured status check interval which is larger than the max
+func (d *Driver) warnStatusCheckIntervals() {
+	_, intervals := d.statusCheckConfig()
+	for name, interval := range map[string]time.Duration{
+		"CheckStatusInterval":         intervals.normal,
+		"HealthyCheckStatusInterval":  intervals.healthy,
+		"FlappingCheckStatusInterval": intervals.flapping,
+	} {
+		if interval > intervals.max {
+			d.lc.Warnf("%s of %v is larger than the maximum value of %v. The maximum value will be used.", name, interval, intervals.max)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusSchedule_record(t *testing.T) {
+	schedule := statusSchedule{}
+	now := time.Now()
+
+	schedule.record(testDeviceName, UpWithAuth, now)
+	device, found := schedule.get(testDeviceName)
+	require.True(t, found)
+	assert.False(t, device.flapping())
+
+	schedule.record(testDeviceName, Unreachable, now.Add(time.Second))
+	device, _ = schedule.get(testDeviceName)
+	assert.True(t, device.flapping())
+	assert.Equal(t, now.Add(time.Second), device.lastCheck)
+
+	for i := 0; i < flappingStableChecks; i++ {
+		device, _ = schedule.get(testDeviceName)
+		assert.True(t, device.flapping())
+		schedule.record(testDeviceName, Unreachable, now.Add(time.Duration(i+2)*time.Second))
+	}
+	device, _ = schedule.get(testDeviceName)
+	assert.False(t, device.flapping())
+
+	schedule.forget(testDeviceName)
+	_, found = schedule.get(testDeviceName)
+	assert.False(t, found)
+}
+
+func TestStatusCheckIntervals_forDevice(t *testing.T) {
+	intervals := statusCheckIntervals{
+		normal:   30 * time.Second,
+		healthy:  120 * time.Second,
+		flapping: 10 * time.Second,
+		max:      300 * time.Second,
+	}
+	flapping := deviceSchedule{status: UpWithAuth, changed: true}
+
+	tests := []struct {
+		name     string
+		override any
+		schedule deviceSchedule
+		expected time.Duration
+	}{
+		{name: "never checked", expected: 30 * time.Second},
+		{name: "reachable", schedule: deviceSchedule{status: Reachable}, expected: 30 * time.Second},
+		{name: "healthy", schedule: deviceSchedule{status: UpWithAuth, stableChecks: 5}, expected: 120 * time.Second},
+		{name: "flapping", schedule: flapping, expected: 10 * time.Second},
+		{name: "override", override: "60", schedule: flapping, expected: 60 * time.Second},
+		{name: "override above max", override: "600", expected: 300 * time.Second},
+		{name: "invalid override", override: "fast", expected: 30 * time.Second},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			device := models.Device{Name: testDeviceName, Protocols: map[string]models.ProtocolProperties{OnvifProtocol: {}}}
+			if test.override != nil {
+				device.Protocols[OnvifProtocol][CheckStatusInterval] = test.override
+			}
+			assert.Equal(t, test.expected, intervals.forDevice(device, test.schedule))
+		})
+	}
+}
+
+func TestDriver_nextStatusChecks(t *testing.T) {
+	now := time.Now()
+	driver, mockService := createDriverWithMockService()
+	driver.config.AppCustom.EnableStatusCheck = true
+	driver.config.AppCustom.CheckStatusInterval = 30
+	driver.config.AppCustom.HealthyCheckStatusInterval = 120
+	driver.config.AppCustom.FlappingCheckStatusInterval = 10
+
+	newDevice := models.Device{Name: "new", Protocols: map[string]models.ProtocolProperties{OnvifProtocol: {}}}
+	healthyDevice := models.Device{Name: "healthy", Protocols: map[string]models.ProtocolProperties{OnvifProtocol: {}}}
+	unreachableDevice := models.Device{Name: "unreachable", Protocols: map[string]models.ProtocolProperties{OnvifProtocol: {}}}
+	mockService.On("Devices").Return([]models.Device{healthyDevice, unreachableDevice})
+
+	driver.statusSchedule.record(healthyDevice.Name, UpWithAuth, now.Add(-time.Minute))
+	driver.statusSchedule.record(unreachableDevice.Name, Unreachable, now.Add(-40*time.Second))
+
+	enabled, intervals := driver.statusCheckConfig()
+	require.True(t, enabled)
+	assert.Equal(t, 300*time.Second, intervals.max)
+
+	due, wait := driver.nextStatusChecks(intervals, now)
+	assert.Equal(t, []models.Device{unreachableDevice}, due)
+	assert.Zero(t, wait)
+
+	driver.statusSchedule.record(unreachableDevice.Name, Unreachable, now)
+	due, wait = driver.nextStatusChecks(intervals, now)
+	assert.Empty(t, due)
+	assert.Equal(t, 30*time.Second, wait)
+
+	// a device which has never been checked is due straight away
+	mockService.ExpectedCalls = nil
+	mockService.On("Devices").Return([]models.Device{healthyDevice, newDevice})
+	due, wait = driver.nextStatusChecks(intervals, now)
+	assert.Equal(t, []models.Device{newDevice}, due)
+	assert.Zero(t, wait)
+
+	// the intervals are read from the latest configuration
+	driver.config.AppCustom.EnableStatusCheck = false
+	driver.config.AppCustom.MaxCheckStatusInterval = 60
+	enabled, intervals = driver.statusCheckConfig()
+	assert.False(t, enabled)
+	assert.Equal(t, 60*time.Second, intervals.forDevice(healthyDevice, deviceSchedule{status: UpWithAuth}))
+}
+
+func TestDriver_updateWritableConfig_statusChecks(t *testing.T) {
+	driver, mockService := createDriverWithMockService()
+	driver.macAddressMapper = NewMACAddressMapper(mockService)
+	driver.config.AppCustom.EnableStatusCheck = true
+	driver.config.AppCustom.CheckStatusInterval = 30
+
+	device := models.Device{Name: testDeviceName, Protocols: map[string]models.ProtocolProperties{OnvifProtocol: {}}}
+	mockService.On("Devices").Return([]models.Device{device})
+	driver.statusSchedule.record(device.Name, UpWithAuth, time.Now())
+
+	// the status of the devices is not checked by the callback, which would need the device from the cache
+	updated := driver.config.AppCustom
+	updated.CheckStatusInterval = 60
+	driver.updateWritableConfig(&updated)
+	assert.Len(t, driver.configChangedCh, 1)
+	_, intervals := driver.statusCheckConfig()
+	due, _ := driver.nextStatusChecks(intervals, time.Now())
+	assert.Empty(t, due)
+	<-driver.configChangedCh
+
+	// the devices are due once the credentials change, and the task loop is woken up to check them
+	updated.CredentialsMap = map[string]string{noAuthSecretName: testMACAddress}
+	driver.updateWritableConfig(&updated)
+	assert.Len(t, driver.configChangedCh, 1)
+	due, _ = driver.nextStatusChecks(intervals, time.Now())
+	assert.Equal(t, []models.Device{device}, due)
+	schedule, found := driver.statusSchedule.get(device.Name)
+	require.True(t, found)
+	assert.Equal(t, UpWithAuth, schedule.status)
+}
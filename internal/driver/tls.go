@@ -0,0 +1,145 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	// CABundleKey is the key of the PEM encoded CA certificates in the secret named by TLSCASecretName
+	CABundleKey = "caBundle"
+)
+
+// deviceTLSSettings holds the settings used to verify the certificate of a camera reached using https
+type deviceTLSSettings struct {
+	// caBundle is the PEM encoded CA certificates loaded from the secret store, or empty to use the system roots
+	caBundle string
+	// fingerprint is the hex encoded SHA-256 fingerprint of the certificate the camera must present, if pinned
+	fingerprint string
+	// insecureSkipVerify disables the verification of the certificate of the camera
+	insecureSkipVerify bool
+}
+
+// certificateFingerprintError is returned when the certificate of a camera does not match its pinned fingerprint
+type certificateFingerprintError struct {
+	expected string
+	actual   string
+}
+
+func (e *certificateFingerprintError) Error() string {
+	return fmt.Sprintf("tls: the certificate fingerprint %s does not match the pinned fingerprint %s", e.actual, e.expected)
+}
+
+// normalizeFingerprint returns the fingerprint in lowercase hex without separators, or an error if it is not
+// a SHA-256 fingerprint
+func normalizeFingerprint(fingerprint string) (string, error) {
+	normalized := strings.ToLower(strings.NewReplacer(":", "", " ", "", "-", "").Replace(fingerprint))
+	if decoded, err := hex.DecodeString(normalized); err != nil || len(decoded) != sha256.Size {
+		return "", fmt.Errorf("invalid SHA-256 certificate fingerprint '%s'", fingerprint)
+	}
+	return normalized, nil
+}
+
+// tlsSettingsForDevice returns the TLS settings of a camera from its protocol properties, and the CA bundle of the
+// TLSCASecretName. Cameras which are not reached using https do not need any settings.
+func (d *Driver) tlsSettingsForDevice(protocols map[string]models.ProtocolProperties) (deviceTLSSettings, errors.EdgeX) {
+	if scheme, _ := locationFromProtocols(protocols); scheme != httpsScheme {
+		return deviceTLSSettings{}, nil
+	}
+
+	settings, edgexErr := d.caBundleTLSSettings()
+	if edgexErr != nil {
+		return deviceTLSSettings{}, errors.NewCommonEdgeXWrapper(edgexErr)
+	}
+	if fingerprint := cast.ToString(protocols[OnvifProtocol][CertificateFingerprint]); fingerprint != "" {
+		normalized, err := normalizeFingerprint(fingerprint)
+		if err != nil {
+			return deviceTLSSettings{}, errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to load the TLS settings", err)
+		}
+		settings.fingerprint = normalized
+	}
+	settings.insecureSkipVerify = cast.ToBool(protocols[OnvifProtocol][InsecureSkipVerify])
+	return settings, nil
+}
+
+// caBundleTLSSettings returns the TLS settings holding the CA bundle of the TLSCASecretName, which are used for every
+// camera, including those being discovered
+func (d *Driver) caBundleTLSSettings() (deviceTLSSettings, errors.EdgeX) {
+	d.configMu.RLock()
+	secretName := d.config.AppCustom.TLSCASecretName
+	d.configMu.RUnlock()
+
+	if secretName == "" {
+		return deviceTLSSettings{}, nil
+	}
+	secretData, err := d.sdkService.SecretProvider().GetSecret(secretName, CABundleKey)
+	if err != nil {
+		return deviceTLSSettings{}, errors.NewCommonEdgeX(errors.KindServerError,
+			fmt.Sprintf("failed to load the CA bundle from the secret name %s", secretName), err)
+	}
+	return deviceTLSSettings{caBundle: secretData[CABundleKey]}, nil
+}
+
+// discoveryTLSConfig returns the TLS config used to reach cameras during discovery, which only trusts the CA bundle
+// of the TLSCASecretName or the system roots, since the per-device settings are not known yet
+func (d *Driver) discoveryTLSConfig() *tls.Config {
+	settings, edgexErr := d.caBundleTLSSettings()
+	if edgexErr == nil {
+		var tlsConfig *tls.Config
+		if tlsConfig, edgexErr = settings.tlsConfig(); edgexErr == nil {
+			return tlsConfig
+		}
+	}
+	d.lc.Warnf("Using the system CA certificates to discover cameras: %s", edgexErr.Error())
+	return nil
+}
+
+// tlsConfig returns the TLS config implementing the settings, or nil if the defaults should be used. A pinned
+// fingerprint is trusted in place of the certificate chain, since cameras commonly use self-signed certificates.
+func (s deviceTLSSettings) tlsConfig() (*tls.Config, errors.EdgeX) {
+	if s == (deviceTLSSettings{}) {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if s.caBundle != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(s.caBundle)) {
+			return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "the CA bundle does not contain any PEM encoded certificates", nil)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if s.fingerprint != "" {
+		expected := s.fingerprint
+		tlsConfig.InsecureSkipVerify = true // #nosec G402
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return &certificateFingerprintError{expected: expected}
+			}
+			actual := sha256.Sum256(state.PeerCertificates[0].Raw)
+			if hex.EncodeToString(actual[:]) != expected {
+				return &certificateFingerprintError{expected: expected, actual: hex.EncodeToString(actual[:])}
+			}
+			return nil
+		}
+	} else if s.insecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true // #nosec G402
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,163 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IOTechSystems/onvif"
+	"github.com/edgexfoundry/go-mod-bootstrap/v4/bootstrap/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeFingerprint(t *testing.T) {
+	fingerprint := "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+
+	tests := []struct {
+		name          string
+		fingerprint   string
+		expected      string
+		errorExpected bool
+	}{
+		{name: "lowercase", fingerprint: fingerprint, expected: fingerprint},
+		{name: "colon separated uppercase", fingerprint: "A1:B2:C3:D4:E5:F6:07:18:29:3A:4B:5C:6D:7E:8F:90:A1:B2:C3:D4:E5:F6:07:18:29:3A:4B:5C:6D:7E:8F:90",
+			expected: fingerprint},
+		{name: "SHA-1", fingerprint: "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4", errorExpected: true},
+		{name: "not hex", fingerprint: "not a fingerprint", errorExpected: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := normalizeFingerprint(test.fingerprint)
+			if test.errorExpected {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDeviceTLSSettings_tlsConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	certificate := server.Certificate()
+	caBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw}))
+	sum := sha256.Sum256(certificate.Raw)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name                string
+		settings            deviceTLSSettings
+		errorExpected       bool
+		connectionSucceeds  bool
+		expectedHealthError string
+	}{
+		{name: "system roots", settings: deviceTLSSettings{}, expectedHealthError: HealthErrorTLS},
+		{name: "CA bundle", settings: deviceTLSSettings{caBundle: caBundle}, connectionSucceeds: true},
+		{name: "invalid CA bundle", settings: deviceTLSSettings{caBundle: "not a certificate"}, errorExpected: true},
+		{name: "pinned fingerprint", settings: deviceTLSSettings{fingerprint: fingerprint}, connectionSucceeds: true},
+		{name: "mismatched fingerprint", settings: deviceTLSSettings{fingerprint: "00" + fingerprint[2:]}, expectedHealthError: HealthErrorTLS},
+		{name: "mismatched fingerprint ignores skip verify", settings: deviceTLSSettings{fingerprint: "00" + fingerprint[2:], insecureSkipVerify: true},
+			expectedHealthError: HealthErrorTLS},
+		{name: "insecure skip verify", settings: deviceTLSSettings{insecureSkipVerify: true}, connectionSucceeds: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			tlsConfig, edgexErr := test.settings.tlsConfig()
+			if test.errorExpected {
+				require.Error(t, edgexErr)
+				return
+			}
+			require.NoError(t, edgexErr)
+
+			client := &http.Client{Timeout: time.Second, Transport: newTLSTransport(tlsConfig)}
+			resp, err := client.Get(server.URL)
+			if test.connectionSucceeds {
+				require.NoError(t, err)
+				_ = resp.Body.Close()
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, test.expectedHealthError, classifyHealthError(err))
+		})
+	}
+}
+
+func TestDriver_tlsSettingsForDevice(t *testing.T) {
+	fingerprint := "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+
+	tests := []struct {
+		name          string
+		secretName    string
+		protocol      models.ProtocolProperties
+		expected      deviceTLSSettings
+		errorExpected bool
+	}{
+		{name: "http", secretName: "ca", protocol: models.ProtocolProperties{InsecureSkipVerify: "true"}},
+		{name: "https without CA bundle", protocol: models.ProtocolProperties{Scheme: httpsScheme, InsecureSkipVerify: "true"},
+			expected: deviceTLSSettings{insecureSkipVerify: true}},
+		{name: "https with CA bundle and fingerprint", secretName: "ca",
+			protocol: models.ProtocolProperties{Scheme: httpsScheme, CertificateFingerprint: fingerprint},
+			expected: deviceTLSSettings{caBundle: "bundle", fingerprint: fingerprint}},
+		{name: "invalid fingerprint", protocol: models.ProtocolProperties{Scheme: httpsScheme, CertificateFingerprint: "abc"},
+			errorExpected: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			driver, mockService := createDriverWithMockService()
+			driver.config.AppCustom.TLSCASecretName = test.secretName
+			mockSecretProvider := &mocks.SecretProvider{}
+			mockSecretProvider.On("GetSecret", "ca", CABundleKey).Return(map[string]string{CABundleKey: "bundle"}, nil)
+			mockService.On("SecretProvider").Return(mockSecretProvider)
+
+			settings, edgexErr := driver.tlsSettingsForDevice(map[string]models.ProtocolProperties{OnvifProtocol: test.protocol})
+			if test.errorExpected {
+				require.Error(t, edgexErr)
+				return
+			}
+			require.NoError(t, edgexErr)
+			assert.Equal(t, test.expected, settings)
+		})
+	}
+}
+
+func TestPullPointManager_newSubscriberOnvifDevice(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	xAddr := server.Listener.Addr().String()
+
+	tlsConfig, edgexErr := deviceTLSSettings{insecureSkipVerify: true}.tlsConfig()
+	require.NoError(t, edgexErr)
+	httpClient := newDeviceServiceHTTPClient(xAddr, httpsScheme, deviceServicePath, time.Second, tlsConfig)
+	device, err := onvif.NewDevice(onvif.DeviceParams{Xaddr: xAddr, HttpClient: httpClient})
+	require.NoError(t, err)
+
+	manager := newPullPointManager(logger.MockLogger{})
+	subscriberDevice, err := manager.newSubscriberOnvifDevice(device, "PT5S", 5)
+	require.NoError(t, err)
+
+	subscriberClient := subscriberDevice.GetDeviceParams().HttpClient
+	assert.Equal(t, 10*time.Second, subscriberClient.Timeout)
+	assert.Same(t, httpClient.Transport, subscriberClient.Transport)
+}
@@ -11,9 +11,13 @@ import (
 	"fmt"
 	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
 	"github.com/edgexfoundry/go-mod-core-contracts/v4/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/spf13/cast"
+	"net"
 	"net/url"
 	"regexp"
 	"strings"
+	"time"
 )
 
 const (
@@ -42,13 +46,12 @@ func (me MultiErr) Error() string {
 }
 
 func addressAndPort(xaddr string) (string, string) {
-	substrings := strings.Split(xaddr, ":")
-	if len(substrings) == 1 {
+	host, port, err := net.SplitHostPort(xaddr)
+	if err != nil {
 		// The port might be empty from the discovered result, for example <d:XAddrs>http://192.168.12.123/onvif/device_service</d:XAddrs>
-		return substrings[0], "80"
-	} else {
-		return substrings[0], substrings[1]
+		return strings.Trim(xaddr, "[]"), httpPort
 	}
+	return host, port
 }
 
 func attributeByKey(attributes map[string]interface{}, key string) (attr string, err errors.EdgeX) {
@@ -78,8 +81,21 @@ func parametersFromURLRawQuery(req sdkModel.CommandRequest) ([]byte, errors.Edge
 
 func buildDeviceName(manufacturer, model, endpointRefAddr string) string {
 	return fmt.Sprintf("%s-%s-%s",
-		// replace all the reserved chars with a dash, and trim any leftovers
-		strings.Trim(rFC3986ReservedCharsRegex.ReplaceAllString(manufacturer, "-"), "-"),
-		strings.Trim(rFC3986ReservedCharsRegex.ReplaceAllString(model, "-"), "-"),
-		strings.Trim(rFC3986ReservedCharsRegex.ReplaceAllString(endpointRefAddr, "-"), "-"))
+		sanitizeDeviceNamePart(manufacturer),
+		sanitizeDeviceNamePart(model),
+		sanitizeDeviceNamePart(endpointRefAddr))
+}
+
+// sanitizeDeviceNamePart replaces all the reserved chars with a dash, and trims any leftovers
+func sanitizeDeviceNamePart(value string) string {
+	return strings.Trim(rFC3986ReservedCharsRegex.ReplaceAllString(value, "-"), "-")
+}
+
+// lastSeen returns the time the device was last seen, or the zero time if it is unknown
+func lastSeen(device models.Device) time.Time {
+	seen, err := time.Parse(time.UnixDate, cast.ToString(device.Protocols[OnvifProtocol][LastSeen]))
+	if err != nil {
+		return time.Time{}
+	}
+	return seen
 }
@@ -46,6 +46,16 @@ func TestAddressAndPort(t *testing.T) {
 			expectedAddress: "localhost",
 			expectedPort:    "80",
 		},
+		{
+			input:           "[fe80::1]:8080",
+			expectedAddress: "fe80::1",
+			expectedPort:    "8080",
+		},
+		{
+			input:           "[fe80::1]",
+			expectedAddress: "fe80::1",
+			expectedPort:    "80",
+		},
 	}
 
 	for _, test := range tests {
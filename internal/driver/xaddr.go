@@ -0,0 +1,237 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"crypto/tls"
+	"encoding/xml"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/IOTechSystems/onvif"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	httpScheme  = "http"
+	httpsScheme = "https"
+	httpPort    = "80"
+)
+
+// deviceServiceLocation describes where the onvif device service of a camera can be reached
+type deviceServiceLocation struct {
+	// XAddrs are all the device service addresses advertised by the camera
+	XAddrs []string
+	// Scheme is the scheme of the selected device service address, either http or https
+	Scheme string
+	// Path is the path of the selected device service address
+	Path string
+}
+
+// probedDevice is an onvif.Device along with the location of its device service, and its advertised scopes
+type probedDevice struct {
+	device   onvif.Device
+	location deviceServiceLocation
+	scopes   []string
+}
+
+// probeMatch holds the fields of a ws-discovery ProbeMatch which are needed to locate a camera
+type probeMatch struct {
+	EndpointReference string `xml:"EndpointReference>Address"`
+	XAddrs            string `xml:"XAddrs"`
+	Scopes            string `xml:"Scopes"`
+}
+
+// probeMatchesEnvelope is the SOAP envelope of a ws-discovery ProbeMatches response
+type probeMatchesEnvelope struct {
+	ProbeMatches []probeMatch `xml:"Body>ProbeMatches>ProbeMatch"`
+}
+
+// deviceServiceTransport sends the requests to the device service of a camera using its advertised scheme and
+// path. The onvif library always addresses the device service as http://<xaddr>/onvif/device_service.
+type deviceServiceTransport struct {
+	base   http.RoundTripper
+	host   string
+	scheme string
+	path   string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *deviceServiceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == t.host && (req.URL.Scheme != t.scheme || (req.URL.Path == deviceServicePath && t.path != deviceServicePath)) {
+		req = req.Clone(req.Context())
+		req.URL.Scheme = t.scheme
+		if req.URL.Path == deviceServicePath {
+			req.URL.Path = t.path
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// locationFromProtocols returns the device service scheme and path of a device, using the defaults if not set
+func locationFromProtocols(protocols map[string]models.ProtocolProperties) (scheme string, path string) {
+	scheme = cast.ToString(protocols[OnvifProtocol][Scheme])
+	if scheme == "" {
+		scheme = httpScheme
+	}
+	path = cast.ToString(protocols[OnvifProtocol][DeviceServicePath])
+	if path == "" {
+		path = deviceServicePath
+	}
+	return scheme, path
+}
+
+// newDeviceServiceHTTPClient creates the http client used by the onvif library to communicate with the camera at
+// xAddr, which addresses the device service using the specified scheme and path. The tlsConfig is used to verify
+// the certificate of the camera, or the defaults if nil.
+func newDeviceServiceHTTPClient(xAddr string, scheme string, path string, timeout time.Duration, tlsConfig *tls.Config) *http.Client {
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: newTLSTransport(tlsConfig),
+	}
+	if scheme != httpScheme || path != deviceServicePath {
+		client.Transport = &deviceServiceTransport{
+			base:   newTLSTransport(tlsConfig),
+			host:   xAddr,
+			scheme: scheme,
+			path:   path,
+		}
+	}
+	return client
+}
+
+// newTLSTransport returns a copy of the default transport using the tlsConfig, or the default transport if nil
+func newTLSTransport(tlsConfig *tls.Config) http.RoundTripper {
+	if tlsConfig == nil {
+		return http.DefaultTransport
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport
+}
+
+// deviceServiceURL returns the full url of the device service of a device
+func deviceServiceURL(xAddr string, protocols map[string]models.ProtocolProperties) string {
+	scheme, path := locationFromProtocols(protocols)
+	return scheme + "://" + xAddr + path
+}
+
+// orderXAddrs parses the space separated XAddrs of a ProbeMatch, and orders them by preference. The https
+// addresses are preferred over the http ones, otherwise the advertised order is kept.
+func orderXAddrs(xaddrs string) []*url.URL {
+	var ordered []*url.URL
+	for _, xaddr := range strings.Fields(xaddrs) {
+		u, err := url.Parse(xaddr)
+		if err != nil || u.Host == "" || (u.Scheme != httpScheme && u.Scheme != httpsScheme) {
+			continue
+		}
+		if u.Port() == "" {
+			port := httpPort
+			if u.Scheme == httpsScheme {
+				port = httpsPort
+			}
+			u.Host = net.JoinHostPort(u.Hostname(), port)
+		}
+		if u.Path == "" {
+			u.Path = deviceServicePath
+		}
+		ordered = append(ordered, u)
+	}
+	slices.SortStableFunc(ordered, func(a, b *url.URL) int {
+		if a.Scheme == b.Scheme {
+			return 0
+		} else if a.Scheme == httpsScheme {
+			return -1
+		}
+		return 1
+	})
+	return ordered
+}
+
+// devicesFromProbeResponses converts the ws-discovery ProbeMatches responses into the devices which answered
+// at one of their advertised XAddrs. The XAddrs are tried in order of preference, and the first one which answers
+// a GetSystemDateAndTime request is selected. The tlsConfig is used to verify the certificate of https XAddrs.
+func devicesFromProbeResponses(responses []string, timeout time.Duration, tlsConfig *tls.Config, lc logger.LoggingClient) []probedDevice {
+	var devices []probedDevice
+	seen := make(map[string]struct{})
+	for _, response := range responses {
+		envelope := probeMatchesEnvelope{}
+		if err := xml.Unmarshal([]byte(response), &envelope); err != nil {
+			lc.Debugf("Unable to parse ws-discovery response: %s", err.Error())
+			continue
+		}
+
+		for _, match := range envelope.ProbeMatches {
+			uuidElements := strings.Split(strings.TrimSpace(match.EndpointReference), ":")
+			endpointRefAddress := uuidElements[len(uuidElements)-1]
+			if _, dupe := seen[endpointRefAddress+match.XAddrs]; dupe {
+				continue
+			}
+			seen[endpointRefAddress+match.XAddrs] = struct{}{}
+
+			device, found := locateDeviceService(endpointRefAddress, match, timeout, tlsConfig, lc)
+			if found {
+				devices = append(devices, device)
+			}
+		}
+	}
+	return devices
+}
+
+// locateDeviceService tries the XAddrs of a ProbeMatch in order of preference, and creates the onvif.Device
+// for the first one which answers.
+func locateDeviceService(endpointRefAddress string, match probeMatch, timeout time.Duration, tlsConfig *tls.Config,
+	lc logger.LoggingClient) (probedDevice, bool) {
+	xaddrs := strings.Fields(match.XAddrs)
+	for _, u := range orderXAddrs(match.XAddrs) {
+		client := &http.Client{Timeout: timeout, Transport: newTLSTransport(tlsConfig)}
+		req, err := newSystemDateAndTimeRequest(u.String())
+		if err != nil {
+			continue
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			lc.Debugf("Camera %s did not answer at %s: %s", endpointRefAddress, u.String(), err.Error())
+			continue
+		}
+		if err = verifySystemDateAndTimeResponse(u.Host, resp); err != nil {
+			lc.Debugf("Camera %s did not answer at %s: %s", endpointRefAddress, u.String(), err.Error())
+			continue
+		}
+
+		dev, err := onvif.NewDevice(onvif.DeviceParams{
+			Xaddr:              u.Host,
+			EndpointRefAddress: endpointRefAddress,
+			HttpClient:         newDeviceServiceHTTPClient(u.Host, u.Scheme, u.Path, timeout, tlsConfig),
+		})
+		if err != nil {
+			lc.Debugf("Failed to connect to camera %s at %s: %s", endpointRefAddress, u.String(), err.Error())
+			continue
+		}
+		scopes := strings.Fields(match.Scopes)
+		dev.SetDeviceInfoFromScopes(scopes)
+
+		return probedDevice{
+			device: *dev,
+			location: deviceServiceLocation{
+				XAddrs: xaddrs,
+				Scheme: u.Scheme,
+				Path:   u.Path,
+			},
+			scopes: scopes,
+		}, true
+	}
+	lc.Debugf("Camera %s did not answer at any of its XAddrs %v", endpointRefAddress, xaddrs)
+	return probedDevice{}, false
+}
@@ -0,0 +1,164 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const probeMatchesTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<SOAP-ENV:Envelope xmlns:SOAP-ENV="http://www.w3.org/2003/05/soap-envelope" xmlns:wsa="http://schemas.xmlsoap.org/ws/2004/08/addressing" xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery">
+<SOAP-ENV:Body><d:ProbeMatches><d:ProbeMatch>
+<wsa:EndpointReference><wsa:Address>urn:uuid:%s</wsa:Address></wsa:EndpointReference>
+<d:Scopes>onvif://www.onvif.org/name/Camera onvif://www.onvif.org/hardware/Model</d:Scopes>
+<d:XAddrs>%s</d:XAddrs>
+</d:ProbeMatch></d:ProbeMatches></SOAP-ENV:Body></SOAP-ENV:Envelope>`
+
+func TestOrderXAddrs(t *testing.T) {
+	tests := []struct {
+		name     string
+		xaddrs   string
+		expected []string
+	}{
+		{
+			name:     "single xaddr",
+			xaddrs:   "http://192.168.1.10/onvif/device_service",
+			expected: []string{"http://192.168.1.10:80/onvif/device_service"},
+		},
+		{
+			name:     "https preferred",
+			xaddrs:   "http://192.168.1.10:8080/onvif/device_service https://192.168.1.10/onvif/device_service",
+			expected: []string{"https://192.168.1.10:443/onvif/device_service", "http://192.168.1.10:8080/onvif/device_service"},
+		},
+		{
+			name:     "advertised order kept",
+			xaddrs:   "http://10.0.0.5/onvif/device_service2 http://[fe80::1]:8000",
+			expected: []string{"http://10.0.0.5:80/onvif/device_service2", "http://[fe80::1]:8000/onvif/device_service"},
+		},
+		{
+			name:     "invalid xaddrs skipped",
+			xaddrs:   "ftp://10.0.0.5/onvif/device_service 10.0.0.6 http://10.0.0.7/onvif/device_service",
+			expected: []string{"http://10.0.0.7:80/onvif/device_service"},
+		},
+		{
+			name:   "empty",
+			xaddrs: "",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var actual []string
+			for _, u := range orderXAddrs(test.xaddrs) {
+				actual = append(actual, u.String())
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDeviceServiceTransport(t *testing.T) {
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requestedPath = request.URL.Path
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		path         string
+		requestPath  string
+		expectedPath string
+	}{
+		{"device service path replaced", "/onvif/device_service2", deviceServicePath, "/onvif/device_service2"},
+		{"other service path kept", "/onvif/device_service2", "/onvif/media_service", "/onvif/media_service"},
+		{"default path", deviceServicePath, deviceServicePath, deviceServicePath},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			client := newDeviceServiceHTTPClient(u.Host, httpScheme, test.path, time.Second, nil)
+			resp, err := client.Post("http://"+u.Host+test.requestPath, "application/soap+xml", nil)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, test.expectedPath, requestedPath)
+		})
+	}
+}
+
+func TestDeviceServiceURL(t *testing.T) {
+	assert.Equal(t, "http://192.168.1.10:80/onvif/device_service",
+		deviceServiceURL("192.168.1.10:80", map[string]models.ProtocolProperties{OnvifProtocol: {}}))
+	assert.Equal(t, "https://192.168.1.10:443/onvif/device_service2",
+		deviceServiceURL("192.168.1.10:443", map[string]models.ProtocolProperties{
+			OnvifProtocol: {Scheme: httpsScheme, DeviceServicePath: "/onvif/device_service2"},
+		}))
+}
+
+func TestDevicesFromProbeResponses(t *testing.T) {
+	const servicePath = "/onvif/device_service2"
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != servicePath {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+		_, err := writer.Write([]byte(systemDateAndTimeResponse))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	unreachable := "http://127.0.0.1:1/onvif/device_service"
+	wrongPath := server.URL + deviceServicePath
+	working := server.URL + servicePath
+
+	tests := []struct {
+		name          string
+		xaddrs        string
+		expectedFound bool
+	}{
+		{"non-standard path", working, true},
+		{"first answering xaddr selected", unreachable + " " + wrongPath + " " + working, true},
+		{"no answering xaddr", unreachable + " " + wrongPath, false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			responses := []string{fmt.Sprintf(probeMatchesTemplate, "1234-5678", test.xaddrs)}
+			devices := devicesFromProbeResponses(responses, time.Second, nil, logger.NewMockClient())
+			if !test.expectedFound {
+				assert.Empty(t, devices)
+				return
+			}
+			require.Len(t, devices, 1)
+			params := devices[0].device.GetDeviceParams()
+			assert.Equal(t, u.Host, params.Xaddr)
+			assert.Equal(t, "1234-5678", params.EndpointRefAddress)
+			assert.Equal(t, httpScheme, devices[0].location.Scheme)
+			assert.Equal(t, servicePath, devices[0].location.Path)
+			assert.Len(t, devices[0].location.XAddrs, len(orderXAddrs(test.xaddrs)))
+		})
+	}
+}
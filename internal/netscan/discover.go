@@ -14,6 +14,7 @@ import (
 	"fmt"
 	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
 	"math"
+	"math/rand/v2"
 	"net"
 	"strings"
 	"sync"
@@ -28,112 +29,164 @@ const (
 	NetworkTCP = "tcp"
 )
 
+// scanPool is a group of subnets which are scanned by the same pool of workers
+type scanPool struct {
+	ipnets     []*net.IPNet
+	asyncLimit int
+	timeout    time.Duration
+	probes     int
+}
+
+// probeInterval returns the interval between probes sent at maxProbesPerSecond, or zero if the rate is unlimited.
+// Rates above one probe per nanosecond are clamped to it, as time.NewTicker panics on a zero interval.
+func probeInterval(maxProbesPerSecond int) time.Duration {
+	if maxProbesPerSecond <= 0 {
+		return 0
+	}
+	return max(time.Second/time.Duration(maxProbesPerSecond), time.Nanosecond)
+}
+
 // AutoDiscover probes all addresses in the configured network to attempt to discover any possible
 // devices for a specific protocol
 func AutoDiscover(ctx context.Context, proto ProtocolSpecificDiscovery, params Params) []sdkModel.DiscoveredDevice {
 	params.Logger.Debugf("AutoDiscover called with the following parameters: %+v", params)
-	if len(params.Subnets) == 0 {
+	if len(params.Subnets) == 0 && len(params.Targets) == 0 {
 		params.Logger.Warn("Discover was called, but no subnet information has been configured!")
 		return nil
 	}
 
-	ipnets := make([]*net.IPNet, 0, len(params.Subnets))
-	var estimatedProbes int
-	for _, cidr := range params.Subnets {
-		if cidr == "" {
-			continue
+	exclusions, err := parseIPRanges(params.Exclusions)
+	if err != nil {
+		// refuse to scan anything, rather than risk probing an address which was meant to be excluded
+		params.Logger.Errorf("Unable to parse discovery exclusions, refusing to scan for devices: %s", err)
+		return nil
+	}
+
+	// all the Subnets share a single pool, and every Target has a pool of its own
+	pools := []*scanPool{newScanPool(params.Subnets, params.AsyncLimit, params.Timeout, params)}
+	for _, target := range params.Targets {
+		asyncLimit := target.AsyncLimit
+		if asyncLimit <= 0 {
+			asyncLimit = params.AsyncLimit
+		}
+		timeout := target.Timeout
+		if timeout <= 0 {
+			timeout = params.Timeout
 		}
+		pools = append(pools, newScanPool([]string{target.Subnet}, asyncLimit, timeout, params))
+	}
 
-		ip, ipnet, err := net.ParseCIDR(cidr)
-		if err != nil {
-			params.Logger.Errorf("Unable to parse CIDR %q: %s", cidr, err)
+	var estimatedProbes int
+	var estimatedTime time.Duration
+	for _, pool := range pools {
+		if pool.probes == 0 {
 			continue
 		}
-		if ip == nil || ipnet == nil || ip.To4() == nil {
-			params.Logger.Errorf("Currently only ipv4 subnets are supported. subnet=%q", cidr)
-			continue
+		estimatedProbes += pool.probes
+		// the pools are scanned in parallel, so the total time is that of the slowest pool
+		rounds := time.Duration(math.Ceil(float64(pool.probes) / float64(pool.asyncLimit)))
+		// on average every host waits for half of the jitter before being probed
+		poolTime := rounds * (pool.timeout + params.Jitter/2)
+		if poolTime > estimatedTime {
+			estimatedTime = poolTime
 		}
-
-		ipnets = append(ipnets, ipnet)
-		// compute the estimate total amount of network probes we are going to make
-		// this is an estimate because it may be lower due to skipped addresses (existing devices)
-		sz, _ := ipnet.Mask.Size()
-		estimatedProbes += int(computeNetSz(sz))
+		params.Logger.Debugf("estimated network probes: %d, async limit: %d, probe timeout: %v for subnets %v",
+			pool.probes, pool.asyncLimit, pool.timeout, pool.ipnets)
 	}
 
 	if estimatedProbes == 0 {
 		params.Logger.Warn("No valid CIDRs provided, unable to scan for devices.")
 		return nil
 	}
+	params.Progress.addEstimated(estimatedProbes)
 
-	// if the estimated amount of probes we are going to make is less than
-	// the async limit, we only need to set the worker count to the total number
-	// of probes to avoid spawning more workers than probes
-	asyncLimit := params.AsyncLimit
-	if estimatedProbes < asyncLimit {
-		asyncLimit = estimatedProbes
+	// when the probe rate is capped, the scan cannot be faster than the time it takes to
+	// send every probe at the maximum rate
+	rateCapped := func(estimate time.Duration, portCount int) time.Duration {
+		if params.MaxProbesPerSecond <= 0 {
+			return estimate
+		}
+		rateTime := time.Duration(float64(estimatedProbes*portCount) / float64(params.MaxProbesPerSecond) * float64(time.Second))
+		return max(estimate, rateTime)
 	}
 
-	probeFactor := time.Duration(math.Ceil(float64(estimatedProbes) / float64(asyncLimit)))
 	portCount := len(params.ScanPorts)
 	var estimatedTimeStr string
 	if portCount == 1 {
-		estimatedTimeStr = cast.ToString(probeFactor * params.Timeout)
+		estimatedTimeStr = cast.ToString(rateCapped(estimatedTime, 1))
 	} else {
+		// typical is just a guess, but have observed it taking around 3x the timeout time when 3
+		// or more ports are scanned.
+		typicalPorts := int(math.Min(float64(portCount), 3))
 		estimatedTimeStr = fmt.Sprintf("min: %s max: %s typical: ~%s",
-			cast.ToString(probeFactor*params.Timeout),
-			cast.ToString(probeFactor*params.Timeout*time.Duration(portCount)),
-			// typical is just a guess, but have observed it taking around 3x the timeout time when 3
-			// or more ports are scanned.
-			cast.ToString(probeFactor*params.Timeout*time.Duration(math.Min(float64(portCount), 3))))
+			cast.ToString(rateCapped(estimatedTime, 1)),
+			cast.ToString(rateCapped(estimatedTime*time.Duration(portCount), portCount)),
+			cast.ToString(rateCapped(estimatedTime*time.Duration(typicalPorts), typicalPorts)))
 	}
-	params.Logger.Debugf("total estimated network probes: %d, async limit: %d, probe timeout: %v, estimated time: %s",
-		estimatedProbes, asyncLimit, params.Timeout, estimatedTimeStr)
-
-	ipCh := make(chan uint32, asyncLimit)
-	resultCh := make(chan []ProbeResult)
-
-	wParams := workerParams{
-		Params:   params,
-		ipCh:     ipCh,
-		resultCh: resultCh,
-		ctx:      ctx,
-		proto:    proto,
+	params.Logger.Debugf("total estimated network probes: %d, excluded ranges: %d, max probes per second: %d, estimated time: %s",
+		estimatedProbes, len(exclusions), params.MaxProbesPerSecond, estimatedTimeStr)
+
+	// a single rate limiter is shared by every pool, so the cap applies to the scan as a whole
+	var rateLimiter <-chan time.Time
+	if interval := probeInterval(params.MaxProbesPerSecond); interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		rateLimiter = ticker.C
 	}
 
-	// start the workers before adding any ips, so they are ready to process
-	var wgIPWorkers sync.WaitGroup
-	wgIPWorkers.Add(asyncLimit)
-	for i := 0; i < asyncLimit; i++ {
-		go func() {
-			defer wgIPWorkers.Done()
-			ipWorker(wParams)
-		}()
+	generator := ipGenerator
+	if params.RandomizeOrder {
+		generator = shuffledIPGenerator
 	}
 
-	go func() {
-		var wgIPGenerators sync.WaitGroup
-		for _, ipnet := range ipnets {
-			select {
-			case <-ctx.Done():
-				// quit early if we have been cancelled
-				return
-			default:
-			}
+	resultCh := make(chan []ProbeResult)
+
+	var wgIPWorkers sync.WaitGroup
+	for _, pool := range pools {
+		if pool.probes == 0 {
+			continue
+		}
 
-			// wait on each ipGenerator
-			wgIPGenerators.Add(1)
-			go func(inet *net.IPNet) {
-				defer wgIPGenerators.Done()
-				ipGenerator(ctx, inet, ipCh)
-			}(ipnet)
+		ipCh := make(chan uint32, pool.asyncLimit)
+		wParams := workerParams{
+			Params:      params,
+			ipCh:        ipCh,
+			resultCh:    resultCh,
+			ctx:         ctx,
+			proto:       proto,
+			exclusions:  exclusions,
+			rateLimiter: rateLimiter,
+		}
+		wParams.Timeout = pool.timeout
+
+		// start the workers before adding any ips, so they are ready to process
+		wgIPWorkers.Add(pool.asyncLimit)
+		for i := 0; i < pool.asyncLimit; i++ {
+			go func() {
+				defer wgIPWorkers.Done()
+				ipWorker(wParams)
+			}()
 		}
 
-		// wait for all ip generators to finish, then we can close the ip channel
-		wgIPGenerators.Wait()
-		close(ipCh)
+		go func(ipnets []*net.IPNet) {
+			var wgIPGenerators sync.WaitGroup
+			for _, ipnet := range ipnets {
+				// wait on each ipGenerator
+				wgIPGenerators.Add(1)
+				go func(inet *net.IPNet) {
+					defer wgIPGenerators.Done()
+					generator(ctx, inet, ipCh)
+				}(ipnet)
+			}
 
-		// wait for the ipWorkers to finish, then close the results channel which
+			// wait for all ip generators to finish, then we can close the ip channel
+			wgIPGenerators.Wait()
+			close(ipCh)
+		}(pool.ipnets)
+	}
+
+	go func() {
+		// wait for the ipWorkers of every pool to finish, then close the results channel which
 		// will let the enclosing function finish
 		wgIPWorkers.Wait()
 		close(resultCh)
@@ -143,6 +196,45 @@ func AutoDiscover(ctx context.Context, proto ProtocolSpecificDiscovery, params P
 	return processResultChannel(resultCh, proto, params)
 }
 
+// newScanPool parses the CIDR formatted subnets into a scanPool, skipping any invalid ones
+func newScanPool(subnets []string, asyncLimit int, timeout time.Duration, params Params) *scanPool {
+	pool := &scanPool{
+		ipnets:  make([]*net.IPNet, 0, len(subnets)),
+		timeout: timeout,
+	}
+	for _, cidr := range subnets {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		ip, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			params.Logger.Errorf("Unable to parse CIDR %q: %s", cidr, err)
+			continue
+		}
+		if ip == nil || ipnet == nil || ip.To4() == nil {
+			params.Logger.Errorf("Currently only ipv4 subnets are supported. subnet=%q", cidr)
+			continue
+		}
+
+		pool.ipnets = append(pool.ipnets, ipnet)
+		// compute the estimate total amount of network probes we are going to make
+		// this is an estimate because it may be lower due to skipped addresses (existing devices)
+		sz, _ := ipnet.Mask.Size()
+		pool.probes += int(computeNetSz(sz))
+	}
+
+	// if the estimated amount of probes we are going to make is less than
+	// the async limit, we only need to set the worker count to the total number
+	// of probes to avoid spawning more workers than probes
+	pool.asyncLimit = asyncLimit
+	if pool.probes < pool.asyncLimit {
+		pool.asyncLimit = pool.probes
+	}
+	return pool
+}
+
 // processResultChannel reads all incoming results until the resultCh is closed.
 // it determines if a device is new or existing, and proceeds accordingly.
 //
@@ -164,6 +256,7 @@ func processResultChannel(resultCh chan []ProbeResult, proto ProtocolSpecificDis
 			// only add if a valid device was returned
 			if device.Name != "" {
 				devices = append(devices, device)
+				params.Progress.deviceConverted()
 			}
 		}
 	}
@@ -182,6 +275,7 @@ func handleConnectionInternal(host string, port string, conn net.Conn, params wo
 		// affect the actual functionality of the service
 		params.Logger.Debug(err.Error())
 	} else if len(results) > 0 {
+		params.Progress.addResponses(len(results))
 		params.resultCh <- results
 	}
 }
@@ -192,6 +286,9 @@ func probe(host string, ports []string, params workerParams) {
 	port0 := ports[0]
 	addr := host + ":" + port0
 
+	if !waitForRateLimit(params) {
+		return
+	}
 	params.Logger.Tracef("Dial: %s", addr)
 	conn, err := net.DialTimeout(params.NetworkProtocol, addr, params.Timeout)
 	if err != nil {
@@ -229,6 +326,9 @@ func probe(host string, ports []string, params workerParams) {
 		go func(p2 string) {
 			defer wg.Done()
 
+			if !waitForRateLimit(params) {
+				return
+			}
 			params.Logger.Tracef("Dial: %s", addr)
 			conn2, err := net.DialTimeout(params.NetworkProtocol, addr, params.Timeout)
 			if err != nil {
@@ -263,16 +363,47 @@ func ipWorker(params workerParams) {
 			}
 
 			binary.BigEndian.PutUint32(ip, uintIp)
+			params.Progress.ipGenerated()
 
 			ipStr := ip.String()
 
+			// never probe any address which has been excluded
+			if isExcluded(params.exclusions, uintIp) {
+				params.Logger.Tracef("Skipping excluded address %s", ipStr)
+				continue
+			}
+
 			// filter out which ports to actually scan, and skip this host if no ports are returned
 			ports := params.proto.ProbeFilter(ipStr, params.ScanPorts)
 			if len(ports) == 0 {
 				continue
 			}
 
+			// spread the probes out over time by waiting a random amount of time before each host
+			if params.Jitter > 0 {
+				select {
+				case <-params.ctx.Done():
+					return
+				case <-time.After(rand.N(params.Jitter)):
+				}
+			}
+
+			params.Progress.ipProbed()
 			probe(ipStr, ports, params)
 		}
 	}
 }
+
+// waitForRateLimit blocks until the rate limiter allows another probe to be sent. It returns
+// false if the scan was cancelled while waiting.
+func waitForRateLimit(params workerParams) bool {
+	if params.rateLimiter == nil {
+		return true
+	}
+	select {
+	case <-params.ctx.Done():
+		return false
+	case <-params.rateLimiter:
+		return true
+	}
+}
@@ -207,3 +207,200 @@ func TestAutoDiscover_MultiPort(t *testing.T) {
 		assert.Contains(t, []string{port1, port2, port3}, result.Protocols["tcp"]["Port"])
 	}
 }
+
+func TestAutoDiscover_Exclusions(t *testing.T) {
+	server, port := startServerWithResponse(t, "Hello World!")
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		exclusions []string
+	}{
+		{
+			name:       "excluded address",
+			exclusions: []string{"127.0.0.1"},
+		},
+		{
+			name:       "excluded range",
+			exclusions: []string{"127.0.0.0-127.0.0.10"},
+		},
+		{
+			name:       "invalid exclusions",
+			exclusions: []string{"127.0.0.1", "not-an-ip"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			params := Params{
+				Subnets:         []string{"127.0.0.1/32"},
+				Exclusions:      test.exclusions,
+				AsyncLimit:      100,
+				Timeout:         time.Duration(100) * time.Millisecond,
+				ScanPorts:       []string{port},
+				Logger:          logger.NewMockClient(),
+				NetworkProtocol: NetworkTCP,
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(),
+				time.Duration(5)*time.Second)
+			defer cancel()
+
+			// no expectations are set on the mock, so any probe of the excluded host will fail the test
+			mockProtocol := MockProtocolSpecificDiscovery{}
+			result := AutoDiscover(ctx, &mockProtocol, params)
+			mockProtocol.AssertExpectations(t)
+			assert.Empty(t, result)
+		})
+	}
+}
+
+func TestAutoDiscover_Targets(t *testing.T) {
+	server, port := startServerWithResponse(t, "Hello World!")
+	defer server.Close()
+
+	params := Params{
+		Targets: []Target{
+			{
+				Subnet:     "127.0.0.1/32",
+				AsyncLimit: 1,
+				Timeout:    time.Duration(200) * time.Millisecond,
+			},
+		},
+		AsyncLimit:      100,
+		Timeout:         time.Duration(100) * time.Millisecond,
+		ScanPorts:       []string{port},
+		Logger:          logger.NewMockClient(),
+		NetworkProtocol: NetworkTCP,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		time.Duration(5)*time.Second)
+	defer cancel()
+
+	mockProtocol := MockProtocolSpecificDiscovery{}
+	probeFilter := mockProtocol.On("ProbeFilter", "127.0.0.1", mock.AnythingOfType("[]string")).Once()
+	probeFilter.Run(func(args mock.Arguments) {
+		probeFilter.Return(args.Get(1).([]string))
+	})
+	mockProtocol.On("OnConnectionDialed", "127.0.0.1", port, mock.Anything, mock.MatchedBy(func(p Params) bool {
+		// the target timeout must be used instead of the global one
+		return p.Timeout == time.Duration(200)*time.Millisecond
+	})).Return([]ProbeResult{{Host: "127.0.0.1", Port: port}}, nil).Once()
+	mockProtocol.On("ConvertProbeResult", mock.Anything, mock.Anything).
+		Return(models.DiscoveredDevice{Name: "test-target-device"}, nil).Once()
+
+	result := AutoDiscover(ctx, &mockProtocol, params)
+	mockProtocol.AssertExpectations(t)
+	require.Len(t, result, 1)
+	assert.Equal(t, "test-target-device", result[0].Name)
+}
+
+func TestAutoDiscover_RateLimit(t *testing.T) {
+	server, port := startServerWithResponse(t, "Hello World!")
+	defer server.Close()
+
+	params := Params{
+		// 6 hosts, of which only 127.0.0.1 is listening
+		Subnets:            []string{"127.0.0.0/29"},
+		AsyncLimit:         100,
+		Timeout:            time.Duration(100) * time.Millisecond,
+		ScanPorts:          []string{port},
+		Logger:             logger.NewMockClient(),
+		NetworkProtocol:    NetworkTCP,
+		MaxProbesPerSecond: 20,
+		Jitter:             time.Duration(10) * time.Millisecond,
+		RandomizeOrder:     true,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		time.Duration(5)*time.Second)
+	defer cancel()
+
+	mockProtocol := MockProtocolSpecificDiscovery{}
+	probeFilter := mockProtocol.On("ProbeFilter", mock.AnythingOfType("string"), mock.AnythingOfType("[]string")).Times(6)
+	probeFilter.Run(func(args mock.Arguments) {
+		probeFilter.Return(args.Get(1).([]string))
+	})
+	mockProtocol.On("OnConnectionDialed", "127.0.0.1", port, mock.Anything, mock.Anything).
+		Return([]ProbeResult{{Host: "127.0.0.1", Port: port}}, nil).Once()
+	mockProtocol.On("ConvertProbeResult", mock.Anything, mock.Anything).
+		Return(models.DiscoveredDevice{Name: "test-rate-limited-device"}, nil).Once()
+
+	t0 := time.Now()
+	result := AutoDiscover(ctx, &mockProtocol, params)
+	elapsed := time.Since(t0)
+	mockProtocol.AssertExpectations(t)
+	require.Len(t, result, 1)
+	assert.Equal(t, "test-rate-limited-device", result[0].Name)
+	// 6 probes at 20 per second cannot be sent in less than 300ms, regardless of the async limit
+	assert.GreaterOrEqual(t, elapsed, time.Duration(300)*time.Millisecond)
+}
+
+func TestProbeInterval(t *testing.T) {
+	tests := []struct {
+		name               string
+		maxProbesPerSecond int
+		expected           time.Duration
+	}{
+		{"unlimited", 0, 0},
+		{"negative", -1, 0},
+		{"one per second", 1, time.Second},
+		{"one per nanosecond", int(time.Second), time.Nanosecond},
+		{"above one per nanosecond", int(time.Second) + 1, time.Nanosecond},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			interval := probeInterval(test.maxProbesPerSecond)
+			assert.Equal(t, test.expected, interval)
+			if interval > 0 {
+				assert.NotPanics(t, func() { time.NewTicker(interval).Stop() })
+			}
+		})
+	}
+}
+
+func TestAutoDiscover_Progress(t *testing.T) {
+	server, port := startServerWithResponse(t, "Hello World!")
+	defer server.Close()
+
+	progress := &Progress{}
+	params := Params{
+		Subnets:         []string{"127.0.0.0/30"},
+		Exclusions:      []string{"127.0.0.2"},
+		AsyncLimit:      100,
+		Timeout:         time.Duration(100) * time.Millisecond,
+		ScanPorts:       []string{port},
+		Logger:          logger.NewMockClient(),
+		NetworkProtocol: NetworkTCP,
+		Progress:        progress,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		time.Duration(5)*time.Second)
+	defer cancel()
+
+	mockProtocol := MockProtocolSpecificDiscovery{}
+	probeFilter := mockProtocol.On("ProbeFilter", "127.0.0.1", mock.AnythingOfType("[]string")).Once()
+	probeFilter.Run(func(args mock.Arguments) {
+		probeFilter.Return(args.Get(1).([]string))
+	})
+	mockProtocol.On("OnConnectionDialed", "127.0.0.1", port, mock.Anything, mock.Anything).
+		Return([]ProbeResult{{Host: "127.0.0.1", Port: port}}, nil).Once()
+	mockProtocol.On("ConvertProbeResult", mock.Anything, mock.Anything).
+		Return(models.DiscoveredDevice{Name: "test-progress-device"}, nil).Once()
+
+	result := AutoDiscover(ctx, &mockProtocol, params)
+	mockProtocol.AssertExpectations(t)
+	require.Len(t, result, 1)
+	assert.Equal(t, ProgressSnapshot{
+		EstimatedProbes:  2,
+		IPsGenerated:     2,
+		IPsProbed:        1,
+		Responses:        1,
+		DevicesConverted: 1,
+	}, progress.Snapshot())
+}
@@ -0,0 +1,80 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package netscan
+
+import (
+	"sync/atomic"
+)
+
+// Progress keeps track of the progress of one or more netscans. It is safe for concurrent use,
+// and a nil Progress ignores all updates.
+type Progress struct {
+	estimated atomic.Uint64
+	generated atomic.Uint64
+	probed    atomic.Uint64
+	responded atomic.Uint64
+	converted atomic.Uint64
+}
+
+// ProgressSnapshot is a point in time copy of the counters of a Progress
+type ProgressSnapshot struct {
+	// EstimatedProbes is the estimated amount of IP addresses which will be probed
+	EstimatedProbes uint64
+	// IPsGenerated is the amount of IP addresses which have been handed to the workers so far,
+	// including excluded and filtered addresses
+	IPsGenerated uint64
+	// IPsProbed is the amount of IP addresses which have been probed so far
+	IPsProbed uint64
+	// Responses is the amount of valid protocol responses which have been received so far
+	Responses uint64
+	// DevicesConverted is the amount of responses which have been converted into discovered devices so far
+	DevicesConverted uint64
+}
+
+// Snapshot returns the current values of the progress counters
+func (p *Progress) Snapshot() ProgressSnapshot {
+	if p == nil {
+		return ProgressSnapshot{}
+	}
+	return ProgressSnapshot{
+		EstimatedProbes:  p.estimated.Load(),
+		IPsGenerated:     p.generated.Load(),
+		IPsProbed:        p.probed.Load(),
+		Responses:        p.responded.Load(),
+		DevicesConverted: p.converted.Load(),
+	}
+}
+
+func (p *Progress) addEstimated(probes int) {
+	if p != nil {
+		p.estimated.Add(uint64(probes))
+	}
+}
+
+func (p *Progress) ipGenerated() {
+	if p != nil {
+		p.generated.Add(1)
+	}
+}
+
+func (p *Progress) ipProbed() {
+	if p != nil {
+		p.probed.Add(1)
+	}
+}
+
+func (p *Progress) addResponses(count int) {
+	if p != nil {
+		p.responded.Add(uint64(count))
+	}
+}
+
+func (p *Progress) deviceConverted() {
+	if p != nil {
+		p.converted.Add(1)
+	}
+}
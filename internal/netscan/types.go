@@ -46,16 +46,39 @@ type ProbeResult struct {
 type workerParams struct {
 	Params
 
-	proto    ProtocolSpecificDiscovery
-	ipCh     <-chan uint32
-	resultCh chan<- []ProbeResult
-	ctx      context.Context
+	proto      ProtocolSpecificDiscovery
+	ipCh       <-chan uint32
+	resultCh   chan<- []ProbeResult
+	ctx        context.Context
+	exclusions []ipRange
+	// rateLimiter delivers a tick every time a probe is allowed to be sent. It is nil when
+	// the probe rate is unlimited.
+	rateLimiter <-chan time.Time
+}
+
+// Target is a subnet to scan with its own scan settings. Each Target is scanned by a separate
+// pool of workers, independently of the Params.Subnets.
+type Target struct {
+	// Subnet is a CIDR formatted subnet to scan
+	Subnet string
+	// AsyncLimit is the maximum amount of hosts in this subnet to probe simultaneously.
+	// If zero, Params.AsyncLimit is used.
+	AsyncLimit int
+	// Timeout is the maximum amount of time to wait when connecting to a host in this subnet.
+	// If zero, Params.Timeout is used.
+	Timeout time.Duration
 }
 
 // Params is the input configuration for a Discovery Net Scan
 type Params struct {
-	// Subnets is a slice of CIDR formatted subnets to scan
+	// Subnets is a slice of CIDR formatted subnets to scan. All of these subnets share the
+	// same pool of workers.
 	Subnets []string
+	// Targets is a slice of subnets to scan with their own AsyncLimit and Timeout
+	Targets []Target
+	// Exclusions is a slice of IP addresses, CIDR formatted subnets and IP ranges (start-end)
+	// which will never be probed.
+	Exclusions []string
 	// ScanPorts is a slice of ports to scan for on each host. The first port is done synchronously
 	// to test if the host is reachable, and any ports after that are done async.
 	ScanPorts []string
@@ -66,6 +89,18 @@ type Params struct {
 	NetworkProtocol string
 	// Timeout is the maximum amount of time to wait when connecting to a host before giving up.
 	Timeout time.Duration
+	// MaxProbesPerSecond is the maximum amount of probes to send per second across all subnets and
+	// targets. If zero, the probe rate is only limited by the AsyncLimit and Timeout.
+	MaxProbesPerSecond int
+	// Jitter is the maximum random delay added before probing each host, in order to spread the
+	// probes out over time.
+	Jitter time.Duration
+	// RandomizeOrder causes the hosts of each subnet to be probed in a random order instead of
+	// sequentially.
+	RandomizeOrder bool
+	// Progress is optionally used to publish the progress of the scan while it is running. The
+	// same Progress may be shared by consecutive scans, in which case the counters accumulate.
+	Progress *Progress
 	// Logger is a generic logging client for this code to log messages to.
 	Logger logger.LoggingClient
 }
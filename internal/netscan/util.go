@@ -9,10 +9,19 @@ package netscan
 import (
 	"context"
 	"encoding/binary"
+	"fmt"
 	"math/bits"
+	"math/rand/v2"
 	"net"
+	"strings"
 )
 
+// ipRange is an inclusive range of IPv4 addresses
+type ipRange struct {
+	start uint32
+	end   uint32
+}
+
 // computeNetSz computes the total amount of valid IP addresses for a given subnet size
 // Subnets of size 31 and 32 have only 1 valid IP address
 // Ex. For a /24 subnet, computeNetSz(24) -> 254
@@ -26,40 +35,131 @@ func computeNetSz(subnetSz int) uint32 {
 // ipGenerator generates all valid IP addresses for a given subnet, and
 // sends them to the ip channel one at a time
 func ipGenerator(ctx context.Context, inet *net.IPNet, ipCh chan<- uint32) {
-	if inet == nil {
+	first, count := hostRange(inet)
+	for i := uint32(0); i < count; i++ {
+		select {
+		case <-ctx.Done():
+			// bail if we have been cancelled
+			return
+		case ipCh <- first + i:
+		}
+	}
+}
+
+// shuffledIPGenerator generates all valid IP addresses for a given subnet in a random order, and
+// sends them to the ip channel one at a time. The order is produced by a full period linear
+// congruential generator, so that even the largest subnets do not need to be held in memory.
+func shuffledIPGenerator(ctx context.Context, inet *net.IPNet, ipCh chan<- uint32) {
+	first, count := hostRange(inet)
+	if count == 0 {
 		return
 	}
+
+	// the modulus is the smallest power of 2 which holds all the hosts. An increment which is odd and a
+	// multiplier where (a - 1) is a multiple of 4 guarantee that every value below the modulus is visited
+	// exactly once, and the values outside the subnet are skipped.
+	mask := uint64(1)<<bits.Len32(count) - 1
+	a := (rand.Uint64()<<2 | 1) & mask
+	c := (rand.Uint64() | 1) & mask
+	x := rand.Uint64() & mask
+	for i := uint64(0); i <= mask; i++ {
+		x = (a*x + c) & mask
+		if x >= uint64(count) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			// bail if we have been cancelled
+			return
+		case ipCh <- first + uint32(x):
+		}
+	}
+}
+
+// hostRange returns the first valid IP address of a given subnet, and the amount of valid IP addresses
+// following it. A count of zero is returned for unsupported subnets.
+func hostRange(inet *net.IPNet) (first uint32, count uint32) {
+	if inet == nil {
+		return 0, 0
+	}
 	addr := inet.IP.To4()
 	if addr == nil {
-		return
+		return 0, 0
 	}
 
 	if len(inet.Mask) != net.IPv4len {
-		return
+		return 0, 0
 	}
 
 	umask := binary.BigEndian.Uint32(inet.Mask)
 	maskSz := bits.OnesCount32(umask)
 	if maskSz <= 1 {
-		return // skip subnet-zero mask
+		return 0, 0 // skip subnet-zero mask
 	} else if maskSz >= 31 {
 		// on /31 and /32 subnets, just return the ip back
-		ipCh <- binary.BigEndian.Uint32(inet.IP)
-		return
+		return binary.BigEndian.Uint32(addr), 1
 	}
 
 	netId := binary.BigEndian.Uint32(addr) & umask // network ID
-	bcast := netId ^ (^umask)
-	for ip := netId + 1; ip < bcast; ip++ {
-		if netId&umask != ip&umask {
+	return netId + 1, computeNetSz(maskSz)
+}
+
+// parseIPRanges parses IPv4 addresses (X.X.X.X), CIDR formatted subnets (X.X.X.X/Y) and
+// IP ranges (X.X.X.X-Y.Y.Y.Y) into a slice of ipRange. Empty values are skipped.
+func parseIPRanges(values []string) ([]ipRange, error) {
+	ranges := make([]ipRange, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
 			continue
 		}
 
-		select {
-		case <-ctx.Done():
-			// bail if we have been cancelled
-			return
-		case ipCh <- ip:
+		if strings.Contains(value, "/") {
+			_, ipnet, err := net.ParseCIDR(value)
+			if err != nil || ipnet.IP.To4() == nil || len(ipnet.Mask) != net.IPv4len {
+				return nil, fmt.Errorf("invalid ipv4 subnet %q", value)
+			}
+			start := binary.BigEndian.Uint32(ipnet.IP.To4())
+			ranges = append(ranges, ipRange{start: start, end: start | ^binary.BigEndian.Uint32(ipnet.Mask)})
+			continue
+		}
+
+		startStr, endStr, isRange := strings.Cut(value, "-")
+		if !isRange {
+			endStr = startStr
+		}
+		start, err := parseIPv4(startStr)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseIPv4(endStr)
+		if err != nil {
+			return nil, err
+		}
+		if start > end {
+			return nil, fmt.Errorf("invalid ip range %q, start address is after the end address", value)
+		}
+		ranges = append(ranges, ipRange{start: start, end: end})
+	}
+	return ranges, nil
+}
+
+// parseIPv4 parses an IPv4 address into its uint32 representation
+func parseIPv4(value string) (uint32, error) {
+	ip := net.ParseIP(strings.TrimSpace(value)).To4()
+	if ip == nil {
+		return 0, fmt.Errorf("invalid ipv4 address %q", value)
+	}
+	return binary.BigEndian.Uint32(ip), nil
+}
+
+// isExcluded returns true if the ip is within any of the ranges
+func isExcluded(ranges []ipRange, ip uint32) bool {
+	for _, r := range ranges {
+		if ip >= r.start && ip <= r.end {
+			return true
 		}
 	}
+	return false
 }
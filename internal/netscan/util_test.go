@@ -186,6 +186,43 @@ func TestIpGenerator(t *testing.T) {
 	}
 }
 
+// TestShuffledIPGenerator validates that the shuffled ip generator produces every valid ip of
+// the subnet exactly once.
+func TestShuffledIPGenerator(t *testing.T) {
+	tests := []struct {
+		name  string
+		cidr  string
+		first uint32
+	}{
+		{name: "/32 subnet", cidr: "192.168.1.110/32", first: 0xC0A8016E},
+		{name: "/31 subnet", cidr: "192.168.1.20/31", first: 0xC0A80114},
+		{name: "/30 subnet", cidr: "192.168.1.1/30", first: 0xC0A80101},
+		{name: "/24 subnet", cidr: "192.168.1.110/24", first: 0xC0A80101},
+		{name: "/20 subnet", cidr: "10.10.10.10/20", first: 0x0A0A0001},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			inet := mustParseCIDR(t, test.cidr)
+			sz, _ := inet.Mask.Size()
+			size := computeNetSz(sz)
+
+			ipCh := make(chan uint32, size)
+			shuffledIPGenerator(context.Background(), inet, ipCh)
+			close(ipCh)
+
+			seen := make(map[uint32]struct{}, size)
+			for ip := range ipCh {
+				require.GreaterOrEqual(t, ip, test.first)
+				require.Less(t, ip, test.first+size)
+				seen[ip] = struct{}{}
+			}
+			assert.Len(t, seen, int(size))
+		})
+	}
+}
+
 func TestIPGeneratorTimeoutCancel(t *testing.T) {
 	var result inetTestResult
 	var wg sync.WaitGroup
@@ -225,3 +262,80 @@ func TestIpGeneratorSubnetSizes(t *testing.T) {
 		})
 	}
 }
+
+func TestParseIPRanges(t *testing.T) {
+	tests := []struct {
+		name          string
+		values        []string
+		included      []string
+		excluded      []string
+		errorExpected bool
+	}{
+		{
+			name:     "empty values",
+			values:   []string{"", " "},
+			excluded: []string{"192.168.1.1"},
+		},
+		{
+			name:     "single address",
+			values:   []string{"192.168.1.1"},
+			included: []string{"192.168.1.1"},
+			excluded: []string{"192.168.1.0", "192.168.1.2"},
+		},
+		{
+			name:     "subnet",
+			values:   []string{"192.168.1.0/28"},
+			included: []string{"192.168.1.0", "192.168.1.7", "192.168.1.15"},
+			excluded: []string{"192.168.0.255", "192.168.1.16"},
+		},
+		{
+			name:     "range",
+			values:   []string{" 10.0.0.200 - 10.0.1.10 "},
+			included: []string{"10.0.0.200", "10.0.0.255", "10.0.1.10"},
+			excluded: []string{"10.0.0.199", "10.0.1.11"},
+		},
+		{
+			name:     "multiple values",
+			values:   []string{"10.0.0.1", "10.0.0.100-10.0.0.110"},
+			included: []string{"10.0.0.1", "10.0.0.105"},
+			excluded: []string{"10.0.0.2", "10.0.0.111"},
+		},
+		{
+			name:          "invalid address",
+			values:        []string{"10.0.0"},
+			errorExpected: true,
+		},
+		{
+			name:          "invalid subnet",
+			values:        []string{"10.0.0.0/33"},
+			errorExpected: true,
+		},
+		{
+			name:          "ipv6 address",
+			values:        []string{"2001:4860:4860::8888"},
+			errorExpected: true,
+		},
+		{
+			name:          "reversed range",
+			values:        []string{"10.0.0.10-10.0.0.1"},
+			errorExpected: true,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			ranges, err := parseIPRanges(test.values)
+			if test.errorExpected {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, ip := range test.included {
+				assert.True(t, isExcluded(ranges, binary.BigEndian.Uint32(net.ParseIP(ip).To4())), ip)
+			}
+			for _, ip := range test.excluded {
+				assert.False(t, isExcluded(ranges, binary.BigEndian.Uint32(net.ParseIP(ip).To4())), ip)
+			}
+		})
+	}
+}